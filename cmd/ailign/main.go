@@ -22,7 +22,7 @@ func main() {
 		if !errors.Is(err, cli.ErrAlreadyReported) {
 			_, _ = fmt.Fprintln(os.Stderr, err)
 		}
-		os.Exit(2)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
@@ -89,10 +89,7 @@ func (w *testWorld) theDeveloperRunsValidate() error {
 	defer func() { _ = os.Chdir(origDir) }()
 
 	err := rootCmd.Execute()
-	w.exitCode = 0
-	if err != nil {
-		w.exitCode = 2
-	}
+	w.exitCode = cli.ExitCode(err)
 	w.stdout = stdoutBuf.String()
 	w.stderr = stderrBuf.String()
 	return nil
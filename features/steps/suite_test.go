@@ -10,6 +10,9 @@ import (
 )
 
 func TestFeatures(t *testing.T) {
+	// Scenarios expect the human format, also when CI runs in GitHub Actions
+	t.Setenv("GITHUB_ACTIONS", "")
+
 	format := "pretty"
 	if output := os.Getenv("CUCUMBER_REPORT"); output != "" {
 		format = "cucumber:" + output
@@ -82,9 +82,5 @@ func (w *testWorld) executeCommand(args []string) {
 	err := rootCmd.Execute()
 	w.stdout = stdoutBuf.String()
 	w.stderr = stderrBuf.String()
-	if err != nil {
-		w.exitCode = 2
-	} else {
-		w.exitCode = 0
-	}
+	w.exitCode = cli.ExitCode(err)
 }
@@ -119,4 +119,4 @@ Feature: Sync Local Instructions to Target Files
     And the target file ".cursorrules" is not writable
     When the developer runs ailign sync
     Then it will report an error containing "permission" to stderr
-    And it will exit with code 2
+    And it will exit with code 3
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ailign/cli/internal/output"
+	"github.com/ailign/cli/internal/sync"
+	"github.com/ailign/cli/internal/target"
+	"github.com/spf13/cobra"
+)
+
+func newCheckCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Check that every instruction file is in sync with the overlays",
+		Long: `Validates .ailign.yml, composes the overlays and compares the result with the hub and target files on disk, without writing anything. Intended for CI. Hand edits to the hub or target files are found through .ailign/state.json or, when it is missing (it is never committed, so CI clones don't have it), through the version and digests recorded in each file's managed header.
+
+Exit codes (shared by every ailign command):
+  0  success; for check, every instruction file is in sync
+  1  drift: sync would change the hub or a target file, or one of them was edited by hand
+  2  invalid configuration, overlays or command-line usage
+  3  I/O or internal error
+  4  policy check found violations of an organization policy`,
+		Args: cobra.NoArgs,
+		RunE: runCheck,
+	}
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return exitWith(ExitInvalid)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	result, err := sync.Sync(cwd, cfg, target.NewDefaultRegistry(), sync.SyncOptions{
+		DryRun:  true,
+		Version: cmd.Root().Version,
+	})
+	var modified *sync.ModifiedError
+	if errors.As(err, &modified) {
+		checkResult := output.CheckResult{Drift: editedDrift(modified)}
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), getCheckFormatter(formatFlag).FormatCheck(checkResult))
+		return exitWith(ExitDrift)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(syncErrorCode(err))
+	}
+
+	for _, w := range result.Warnings {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+	}
+
+	drift, errs := findDrift(result, cwd)
+	checkResult := output.CheckResult{
+		InSync: len(drift) == 0 && len(errs) == 0,
+		Drift:  drift,
+		Errors: errs,
+	}
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), getCheckFormatter(formatFlag).FormatCheck(checkResult))
+
+	switch {
+	case len(errs) > 0:
+		return exitWith(ExitError)
+	case len(drift) > 0:
+		return exitWith(ExitDrift)
+	}
+	return nil
+}
+
+// findDrift lists each change a dry-run sync found, and each target it
+// could not check.
+func findDrift(r *sync.SyncResult, baseDir string) (drift []output.DriftEntry, errs []string) {
+	if r.HubStatus == "written" {
+		drift = append(drift, output.DriftEntry{Path: displayPath(baseDir, r.HubPath), Change: "written"})
+	}
+	for _, l := range r.Links {
+		switch l.Status {
+		case "created", "replaced", "removed":
+			drift = append(drift, output.DriftEntry{Path: filepath.ToSlash(l.LinkPath), Change: l.Status})
+		case "error":
+			errs = append(errs, fmt.Sprintf("%s: %s", l.Target, l.Error))
+		}
+	}
+	for _, g := range r.GitFiles {
+		if g.Status == "error" {
+			errs = append(errs, g.Error)
+		} else {
+			drift = append(drift, output.DriftEntry{Path: g.Path, Change: g.Status})
+		}
+	}
+	return drift, errs
+}
+
+// editedDrift lists the files a dry-run sync refused to overwrite.
+func editedDrift(err *sync.ModifiedError) []output.DriftEntry {
+	drift := make([]output.DriftEntry, 0, len(err.Files))
+	for _, f := range err.Files {
+		detail := f.Advice()
+		if len(f.Via) > 0 {
+			detail = fmt.Sprintf("%s (edits to %s land here)", detail, strings.Join(f.Via, ", "))
+		}
+		drift = append(drift, output.DriftEntry{Path: f.Path, Change: "edited", Detail: detail})
+	}
+	return drift
+}
+
+func getCheckFormatter(format string) output.CheckFormatter {
+	switch format {
+	case "json":
+		return &output.JSONFormatter{}
+	case "github":
+		return &output.GitHubFormatter{}
+	default:
+		return &output.HumanFormatter{}
+	}
+}
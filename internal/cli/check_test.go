@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_ExitCodes(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	stdout, _, exitCode := executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	assert.Contains(t, stdout, "2 instruction files out of date with the overlays:")
+	assert.Contains(t, stdout, ".claude/instructions.md")
+	_, err := os.Stat(filepath.Join(dir, ".ailign"))
+	assert.True(t, os.IsNotExist(err), "check must not write anything")
+
+	_, _, exitCode = executeCommand([]string{"sync"}, dir)
+	require.Equal(t, ExitOK, exitCode)
+	stdout, stderr, exitCode := executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	assert.Equal(t, "All instruction files are in sync.\n", stdout)
+
+	writeOverlay(t, dir, "base.md", "# Base\n\nEdited.\n")
+	stdout, _, exitCode = executeCommand([]string{"check", "--format", "json"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	var parsed struct {
+		InSync bool `json:"in_sync"`
+		Drift  []struct {
+			Path   string `json:"path"`
+			Change string `json:"change"`
+		} `json:"drift"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &parsed), stdout)
+	assert.False(t, parsed.InSync)
+	require.Len(t, parsed.Drift, 1)
+	assert.Equal(t, ".ailign/instructions.md", parsed.Drift[0].Path)
+	assert.Equal(t, "written", parsed.Drift[0].Change)
+}
+
+func TestCheck_InvalidInput_ExitTwo(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, dir string)
+	}{
+		{
+			name: "invalid config",
+			setup: func(t *testing.T, dir string) {
+				writeOverlay(t, dir, ".ailign.yml", "targets:\n  - vscode\n")
+			},
+		},
+		{
+			name:  "missing config",
+			setup: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "missing overlay",
+			setup: func(t *testing.T, dir string) {
+				writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"missing.md"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(t, dir)
+			_, _, exitCode := executeCommand([]string{"check"}, dir)
+			assert.Equal(t, ExitInvalid, exitCode)
+		})
+	}
+}
+
+func TestCheck_UnreadableOverlay_ExitThree(t *testing.T) {
+	skipSyncOnWindows(t)
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files regardless of permissions")
+	}
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	require.NoError(t, os.Chmod(filepath.Join(dir, "base.md"), 0))
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(dir, "base.md"), 0644) })
+
+	_, stderr, exitCode := executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitError, exitCode)
+	assert.Contains(t, stderr, "permission denied")
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"drift", exitWith(ExitDrift), ExitDrift},
+		{"wrapped", fmt.Errorf("running: %w", exitWith(ExitInvalid)), ExitInvalid},
+		{"reported without a code", ErrAlreadyReported, ExitError},
+		{"usage error from cobra", errors.New(`unknown flag: --bogus`), ExitInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+
+	assert.ErrorIs(t, exitWith(ExitDrift), ErrAlreadyReported)
+	assert.Equal(t, ExitInvalid, syncErrorCode(&sync.InputError{Err: errors.New("bad overlay")}))
+	assert.Equal(t, ExitError, syncErrorCode(errors.New("disk full")))
+}
+
+func TestCheck_GitignoreBlockDrift(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	_, _, exitCode := executeCommand([]string{"sync"}, dir)
+	require.Equal(t, ExitOK, exitCode)
+
+	cfg, err := os.ReadFile(filepath.Join(dir, ".ailign.yml"))
+	require.NoError(t, err)
+	writeOverlay(t, dir, ".ailign.yml", string(cfg)+"commit_outputs: false\n")
+
+	stdout, _, exitCode := executeCommand([]string{"sync", "--dry-run"}, dir)
+	assert.Equal(t, ExitOK, exitCode)
+	assert.Contains(t, stdout, "would update ailign block")
+	stdout, _, exitCode = executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	assert.Contains(t, stdout, ".gitignore")
+
+	_, _, exitCode = executeCommand([]string{"sync"}, dir)
+	require.Equal(t, ExitOK, exitCode)
+	assert.FileExists(t, filepath.Join(dir, ".gitignore"))
+	_, _, exitCode = executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitOK, exitCode)
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/ailign/cli/internal/sync"
+)
+
+// Exit codes. They are part of the CLI's interface: CI scripts branch on
+// them, so their meaning must not change.
+const (
+	ExitOK      = 0 // success; for check, every output is in sync
+	ExitDrift   = 1 // outputs are out of date with the overlays, or were edited by hand
+	ExitInvalid = 2 // invalid configuration, overlays, policy file or command-line usage
+	ExitError   = 3 // I/O or internal error
+	ExitPolicy  = 4 // the repository violates an organization policy
+)
+
+// exitError is an error that has already been reported to the user and
+// carries the exit code for it.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string { return ErrAlreadyReported.Error() }
+
+// Is makes errors.Is(err, ErrAlreadyReported) hold, so main doesn't
+// print it again.
+func (e *exitError) Is(target error) bool { return target == ErrAlreadyReported }
+
+// exitWith returns an already-reported error that exits with code.
+func exitWith(code int) error {
+	return &exitError{code: code}
+}
+
+// ExitCode returns the process exit code for an error returned by
+// executing the root command. Errors the commands didn't report
+// themselves come from argument and flag parsing, and count as usage
+// errors.
+func ExitCode(err error) int {
+	var exitErr *exitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	case errors.Is(err, ErrAlreadyReported):
+		return ExitError
+	default:
+		return ExitInvalid
+	}
+}
+
+// syncErrorCode classifies an error from the sync package: outputs
+// edited by hand are drift, problems with the configuration or overlays
+// are invalid input, anything else is an I/O or internal error.
+func syncErrorCode(err error) int {
+	var input *sync.InputError
+	var modified *sync.ModifiedError
+	switch {
+	case errors.As(err, &modified):
+		return ExitDrift
+	case errors.As(err, &input):
+		return ExitInvalid
+	}
+	return ExitError
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ailign/cli/internal/hooks"
+	"github.com/ailign/cli/internal/sync"
+	"github.com/ailign/cli/internal/target"
+	"github.com/spf13/cobra"
+)
+
+func newHooksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage the git pre-commit hook that catches instruction drift",
+		Long:  "Installs a git pre-commit hook that refuses commits while the hub or target files are out of date with the overlays, so drift is caught before it reaches CI.",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "install",
+		Short: "Install the pre-commit hook",
+		Long:  "Writes a pre-commit hook into the repository's hooks directory, honoring core.hooksPath. An existing pre-commit hook is kept and runs after the ailign check. When core.hooksPath names a directory outside the repository, the hook is written there and runs for every repository that uses it; it skips the check in repositories without .ailign.yml.",
+		Args:  cobra.NoArgs,
+		RunE:  runHooksInstall,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the pre-commit hook and restore the previous one",
+		Args:  cobra.NoArgs,
+		RunE:  runHooksUninstall,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:       "run <hook>",
+		Short:     "Run a hook's check (used by the installed hook)",
+		Long:      "Runs the check behind an installed hook. For pre-commit, fails if sync would change the hub or any target file, or if the hub or a target has changes that are not staged: the commit records the index, not the working tree.",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{hooks.HookName},
+		RunE:      runHooksRun,
+	})
+	return cmd
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	cwd, hooksDir, err := hooksDirectory()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	shared, err := hooks.Shared(cwd, hooksDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	result, err := hooks.Install(hooksDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	out := cmd.OutOrStdout()
+	switch result.Status {
+	case "exists":
+		_, _ = fmt.Fprintf(out, "The ailign %s hook is already installed at %s.\n", hooks.HookName, displayPath(cwd, result.Path))
+	case "updated":
+		_, _ = fmt.Fprintf(out, "Updated the ailign %s hook at %s to the current version.\n", hooks.HookName, displayPath(cwd, result.Path))
+	default:
+		_, _ = fmt.Fprintf(out, "Installed the ailign %s hook at %s.\n", hooks.HookName, displayPath(cwd, result.Path))
+		if result.Previous != "" {
+			_, _ = fmt.Fprintf(out, "The existing hook was moved to %s and runs after the ailign check.\n", displayPath(cwd, result.Previous))
+		}
+	}
+
+	if shared {
+		_, _ = fmt.Fprintf(out, "core.hooksPath points outside this repository: the hook runs for every repository using %s, and skips those without .ailign.yml.\n", hooksDir)
+	}
+	return nil
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) error {
+	cwd, hooksDir, err := hooksDirectory()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	result, err := hooks.Uninstall(hooksDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	out := cmd.OutOrStdout()
+	if result.Status == "not_installed" {
+		_, _ = fmt.Fprintf(out, "No %s hook is installed at %s.\n", hooks.HookName, displayPath(cwd, result.Path))
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Removed the ailign %s hook from %s.\n", hooks.HookName, displayPath(cwd, result.Path))
+	if result.Previous != "" {
+		_, _ = fmt.Fprintln(out, "The previous hook was restored.")
+	}
+	return nil
+}
+
+// runHooksRun is the pre-commit check: a dry-run sync that must find
+// nothing to do, and a hub and targets whose working tree contents are
+// the ones staged for the commit.
+func runHooksRun(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return exitWith(ExitInvalid)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	result, err := sync.Sync(cwd, cfg, target.NewDefaultRegistry(), sync.SyncOptions{DryRun: true, Version: cmd.Root().Version})
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(syncErrorCode(err))
+	}
+
+	drift, errs := findDrift(result, cwd)
+	unstaged, err := hooks.Unstaged(cwd, outputPaths(result))
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(drift) == 0 && len(unstaged) == 0 && len(errs) == 0 {
+		return nil
+	}
+	errOut := cmd.ErrOrStderr()
+	for _, e := range errs {
+		_, _ = fmt.Fprintf(errOut, "Error: %s\n", e)
+	}
+	if len(drift) > 0 {
+		_, _ = fmt.Fprintln(errOut, "ailign: instruction files are out of date with the overlays:")
+		for _, d := range drift {
+			_, _ = fmt.Fprintf(errOut, "  %s would be %s\n", d.Path, d.Change)
+		}
+		_, _ = fmt.Fprintln(errOut, `Run "ailign sync" and stage the changes, or commit with --no-verify to skip this check.`)
+	}
+	if len(unstaged) > 0 {
+		_, _ = fmt.Fprintln(errOut, "ailign: instruction files have changes that are not staged for this commit:")
+		for _, p := range unstaged {
+			_, _ = fmt.Fprintf(errOut, "  %s\n", p)
+		}
+		_, _ = fmt.Fprintln(errOut, `Stage them with "git add", or commit with --no-verify to skip this check.`)
+	}
+	if len(errs) > 0 {
+		return exitWith(ExitError)
+	}
+	return exitWith(ExitDrift)
+}
+
+// outputPaths lists the hub and the target files of a sync result.
+func outputPaths(r *sync.SyncResult) []string {
+	paths := []string{r.HubPath}
+	for _, l := range r.Links {
+		if l.LinkPath != "" {
+			paths = append(paths, l.LinkPath)
+		}
+	}
+	return paths
+}
+
+// hooksDirectory returns the working directory and the git hooks
+// directory of the repository containing it.
+func hooksDirectory() (string, string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	dir, err := hooks.Dir(cwd)
+	if err != nil {
+		return "", "", err
+	}
+	return cwd, dir, nil
+}
+
+// displayPath shows p relative to baseDir when it lies inside it.
+func displayPath(baseDir, p string) string {
+	rel, err := filepath.Rel(baseDir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return p
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksRun_FailsOnDrift(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	_, stderr, exitCode := executeCommand([]string{"hooks", "run", "pre-commit"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	assert.Contains(t, stderr, ".ailign/instructions.md would be written")
+	assert.Contains(t, stderr, ".claude/instructions.md would be created")
+
+	_, _, exitCode = executeCommand([]string{"sync"}, dir)
+	require.Equal(t, 0, exitCode)
+	require.NoError(t, exec.Command("git", "-C", dir, "add", "-A").Run())
+	stdout, stderr, exitCode := executeCommand([]string{"hooks", "run", "pre-commit"}, dir)
+	assert.Equal(t, 0, exitCode, "stderr: %s", stderr)
+	assert.Empty(t, stdout)
+
+	writeOverlay(t, dir, "base.md", "# Base\n\nEdited.\n")
+	_, stderr, exitCode = executeCommand([]string{"hooks", "run", "pre-commit"}, dir)
+	assert.NotEqual(t, 0, exitCode)
+	assert.Contains(t, stderr, `Run "ailign sync"`)
+}
+
+func TestHooksRun_FailsOnUnstagedOutputs(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	_, _, exitCode := executeCommand([]string{"sync"}, dir)
+	require.Equal(t, 0, exitCode)
+	require.NoError(t, exec.Command("git", "-C", dir, "add", "-A").Run())
+
+	// The working tree is in sync, but the index holds the old hub
+	writeOverlay(t, dir, "base.md", "# Base\n\nEdited.\n")
+	_, _, exitCode = executeCommand([]string{"sync"}, dir)
+	require.Equal(t, 0, exitCode)
+	_, stderr, exitCode := executeCommand([]string{"hooks", "run", "pre-commit"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	assert.Contains(t, stderr, "not staged for this commit")
+	assert.Contains(t, stderr, ".ailign/instructions.md")
+
+	require.NoError(t, exec.Command("git", "-C", dir, "add", "-A").Run())
+	_, stderr, exitCode = executeCommand([]string{"hooks", "run", "pre-commit"}, dir)
+	assert.Equal(t, 0, exitCode, "stderr: %s", stderr)
+}
+
+func TestHooksRun_UnknownHook(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	_, _, exitCode := executeCommand([]string{"hooks", "run", "post-merge"}, dir)
+	assert.Equal(t, ExitInvalid, exitCode)
+}
+
+func TestHooksInstallUninstall(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	hook := filepath.Join(dir, ".git", "hooks", "pre-commit")
+	require.NoError(t, os.MkdirAll(filepath.Dir(hook), 0755))
+	require.NoError(t, os.WriteFile(hook, []byte("#!/bin/sh\nnpm test\n"), 0755))
+
+	// No .ailign.yml is needed to manage hooks
+	stdout, stderr, exitCode := executeCommand([]string{"hooks", "install"}, dir)
+	require.Equal(t, 0, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "Installed the ailign pre-commit hook at .git/hooks/pre-commit.")
+	assert.Contains(t, stdout, "moved to .git/hooks/pre-commit.ailign-previous")
+
+	stdout, _, exitCode = executeCommand([]string{"hooks", "uninstall"}, dir)
+	require.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "The previous hook was restored.")
+	data, err := os.ReadFile(hook)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nnpm test\n", string(data))
+}
+
+func TestHooksInstall_SharedHooksPath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", dir).Run())
+	shared := filepath.Join(t.TempDir(), "hooks")
+	require.NoError(t, exec.Command("git", "-C", dir, "config", "core.hooksPath", shared).Run())
+
+	stdout, stderr, exitCode := executeCommand([]string{"hooks", "install"}, dir)
+	require.Equal(t, 0, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "core.hooksPath points outside this repository")
+	assert.FileExists(t, filepath.Join(shared, "pre-commit"))
+
+	stdout, _, exitCode = executeCommand([]string{"hooks", "install"}, dir)
+	require.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "already installed")
+	assert.Contains(t, stdout, "core.hooksPath points outside this repository")
+
+	stdout, _, _ = executeCommand([]string{"hooks", "uninstall"}, dir)
+	assert.Contains(t, stdout, "Removed the ailign pre-commit hook")
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"slices"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/lint"
+	"github.com/ailign/cli/internal/target"
+	"github.com/spf13/cobra"
+)
+
+func newLintCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Short: "Check overlay files for content quality issues",
+		Long:  "Checks the configured overlay files and the content each target would receive for quality issues: target size limits (documented for windsurf only; set others under lint.size_limits), missing headings, broken links, trailing whitespace, mixed line endings, empty sections and long lines. Each rule can be set to error, warning or off under the \"lint\" key in .ailign.yml. Does not modify any files.",
+		RunE:  runLint,
+	}
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return exitWith(ExitInvalid)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return reportFailure(cmd, err, ExitError)
+	}
+
+	res := lint.Lint(cwd, cfg, target.NewDefaultRegistry(), cmd.Root().Version)
+	result := &config.ValidationResult{
+		Valid:    len(res.Errors) == 0,
+		Errors:   res.Errors,
+		Warnings: append(slices.Clone(configWarnings), res.Warnings...),
+	}
+
+	outResult := toOutputResult(result, "overlays")
+	outResult.Rules = res.Rules
+	printValidation(cmd, outResult)
+	if !result.Valid {
+		return exitWith(ExitInvalid)
+	}
+	return nil
+}
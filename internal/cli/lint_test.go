@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint_CleanOverlays_ExitZero(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n\nUse gofmt.\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"lint"}, dir)
+
+	assert.Equal(t, 0, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "valid")
+	assert.Empty(t, stderr)
+}
+
+func TestLint_WarningsDoNotFail(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n\ntext  \n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"lint"}, dir)
+
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "1 warning")
+	assert.Contains(t, stderr, "base.md:3: line has trailing whitespace (lint.trailing_whitespace)")
+}
+
+func TestLint_ErrorSeverity_ExitNonZero(t *testing.T) {
+	dir := t.TempDir()
+	writeOverlay(t, dir, ".ailign.yml", "targets:\n  - windsurf\nlocal_overlays:\n  - base.md\n")
+	writeOverlay(t, dir, "base.md", "# Base\n\n"+strings.Repeat("Keep it short.\n", 500))
+
+	stdout, stderr, exitCode := executeCommand([]string{"lint"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, ".windsurfrules: windsurf instructions exceed the tool's size limit")
+}
+
+func TestLint_ErrorsAndWarningsReportedTogether(t *testing.T) {
+	dir := t.TempDir()
+	writeOverlay(t, dir, ".ailign.yml", "targets:\n  - windsurf\nlocal_overlays:\n  - base.md\n")
+	writeOverlay(t, dir, "base.md", "# Base\n\ntext  \n"+strings.Repeat("Keep it short.\n", 500))
+
+	_, stderr, exitCode := executeCommand([]string{"lint"}, dir)
+
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Contains(t, stderr, "base.md:3: line has trailing whitespace (lint.trailing_whitespace)")
+	assert.Contains(t, stderr, ".windsurfrules: windsurf instructions exceed the tool's size limit")
+}
+
+func TestLint_JSONFormat_IncludesLocation(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "No heading here.\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"lint", "--format", "json"}, dir)
+	require.Equal(t, 0, exitCode)
+	assert.Empty(t, stderr)
+
+	var parsed struct {
+		Warnings []struct {
+			FieldPath string `json:"field_path"`
+			File      string `json:"file"`
+			Line      int    `json:"line"`
+		} `json:"warnings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &parsed), "stdout: %s", stdout)
+	require.Len(t, parsed.Warnings, 1)
+	assert.Equal(t, "lint.missing_heading", parsed.Warnings[0].FieldPath)
+	assert.Equal(t, "base.md", parsed.Warnings[0].File)
+	assert.Equal(t, 1, parsed.Warnings[0].Line)
+}
+
+func TestLint_SARIFFormat(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "No heading here.\n")
+
+	stdout, _, exitCode := executeCommand([]string{"lint", "--format", "sarif"}, dir)
+	require.Equal(t, 0, exitCode)
+
+	var parsed struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &parsed), "stdout: %s", stdout)
+	assert.Equal(t, "2.1.0", parsed.Version)
+	require.Len(t, parsed.Runs, 1)
+	require.Len(t, parsed.Runs[0].Results, 1)
+	result := parsed.Runs[0].Results[0]
+	assert.Equal(t, "lint.missing_heading", result.RuleID)
+	assert.Equal(t, "base.md", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 1, result.Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestLint_SARIFFormat_ErrorsAndWarnings(t *testing.T) {
+	dir := t.TempDir()
+	writeOverlay(t, dir, ".ailign.yml", "targets:\n  - windsurf\nlocal_overlays:\n  - base.md\nextra: true\n")
+	writeOverlay(t, dir, "base.md", "# Base\n\ntext  \n"+strings.Repeat("Keep it short.\n", 500))
+
+	stdout, stderr, exitCode := executeCommand([]string{"lint", "--format", "sarif"}, dir)
+
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Empty(t, stderr)
+	assert.Equal(t, map[string]string{
+		"config.unknown_field":     "warning",
+		"lint.target_size":         "error",
+		"lint.trailing_whitespace": "warning",
+	}, sarifLevels(t, stdout))
+}
+
+func TestLint_RuleDisabledInConfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ailign.yml"),
+		[]byte("targets:\n  - claude\nlocal_overlays:\n  - base.md\nlint:\n  missing_heading: off\n"), 0644))
+	writeOverlay(t, dir, "base.md", "No heading here.\n")
+
+	_, stderr, exitCode := executeCommand([]string{"lint"}, dir)
+
+	assert.Equal(t, 0, exitCode)
+	assert.Empty(t, stderr)
+}
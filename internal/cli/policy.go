@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/policy"
+	"github.com/ailign/cli/internal/target"
+	"github.com/spf13/cobra"
+)
+
+var policyFlag string
+
+func newPolicyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Check the repository against an organization policy",
+		Long: `Organization policies mandate parts of every repository's ailign setup. A policy is a YAML file:
+
+  required_targets: [claude, copilot]    # must be listed under targets
+  forbidden_targets: [windsurf]          # must not be listed under targets
+  required_overlays: [docs/security.md]  # must be listed under local_overlays
+  max_instruction_chars: 20000           # per target's rendered content
+  max_instruction_tokens: 5000           # per target's rendered content, approximate
+
+Unknown keys in a policy are errors, so a misspelled rule is never silently skipped.`,
+	}
+	check := &cobra.Command{
+		Use:   "check",
+		Short: "Check .ailign.yml and the rendered instructions against a policy",
+		Long:  "Evaluates the configuration in .ailign.yml and the content each target would receive from sync against the policy given with --policy or, without it, the file named by policy in .ailign.yml. Every violation is reported; the command exits 4 if there are any, and 2 if the policy file itself is missing or invalid. Does not modify any files.",
+		Args:  cobra.NoArgs,
+		RunE:  runPolicyCheck,
+	}
+	check.Flags().StringVar(&policyFlag, "policy", "",
+		"Policy file to check against (overrides policy in .ailign.yml)")
+	cmd.AddCommand(check)
+	return cmd
+}
+
+func runPolicyCheck(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return exitWith(ExitInvalid)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return reportFailure(cmd, err, ExitError)
+	}
+
+	policyPath := policyFlag
+	if policyPath == "" {
+		if cfg.Policy == "" {
+			return reportFailure(cmd, errors.New("no policy to check against: pass --policy or set policy in .ailign.yml"), ExitInvalid)
+		}
+		policyPath = filepath.Join(cwd, filepath.FromSlash(cfg.Policy))
+	}
+
+	registry := target.NewDefaultRegistry()
+	p, err := policy.Load(policyPath, registry)
+	if err != nil {
+		return reportFailure(cmd, err, ExitInvalid)
+	}
+
+	violations, err := policy.Check(cwd, cfg, registry, p, displayPath(cwd, policyPath), cmd.Root().Version)
+	if err != nil {
+		return reportFailure(cmd, err, syncErrorCode(err))
+	}
+
+	result := &config.ValidationResult{Valid: len(violations) == 0, Errors: violations, Warnings: configWarnings}
+	outResult := toOutputResult(result, "policy")
+	outResult.Rules = p.Rules()
+	printValidation(cmd, outResult)
+	if !result.Valid {
+		return exitWith(ExitPolicy)
+	}
+	return nil
+}
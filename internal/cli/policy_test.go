@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	writeOverlay(t, dir, "org/policy.yml", "required_targets: [claude]\nrequired_overlays: [base.md]\n")
+
+	_, stderr, exitCode := executeCommand([]string{"policy", "check"}, dir)
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Contains(t, stderr, "pass --policy or set policy in .ailign.yml")
+
+	_, stderr, exitCode = executeCommand([]string{"policy", "check", "--policy", "org/policy.yml"}, dir)
+	assert.Equal(t, ExitPolicy, exitCode)
+	assert.Contains(t, stderr, "Error: policy validation failed")
+	assert.Contains(t, stderr, `policy.required_targets: target "claude" is required by policy`)
+
+	// The policy referenced from .ailign.yml
+	writeOverlay(t, dir, ".ailign.yml", "targets:\n  - claude\nlocal_overlays:\n  - base.md\npolicy: org/policy.yml\n")
+	stdout, stderr, exitCode := executeCommand([]string{"policy", "check"}, dir)
+	assert.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	assert.Equal(t, "policy: valid\n", stdout)
+}
+
+func TestPolicyCheck_JSON(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	policyPath := filepath.Join(t.TempDir(), "policy.yml")
+	require.NoError(t, os.WriteFile(policyPath, []byte("forbidden_targets: [cursor]\n"), 0644))
+
+	_, stderr, exitCode := executeCommand([]string{"policy", "check", "--policy", policyPath, "--format", "json"}, dir)
+	assert.Equal(t, ExitPolicy, exitCode)
+	var parsed struct {
+		Valid  bool `json:"valid"`
+		Errors []struct {
+			FieldPath string `json:"field_path"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stderr), &parsed), stderr)
+	assert.False(t, parsed.Valid)
+	require.Len(t, parsed.Errors, 1)
+	assert.Equal(t, "policy.forbidden_targets", parsed.Errors[0].FieldPath)
+}
+
+func TestPolicyCheck_JUnit(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	writeOverlay(t, dir, "policy.yml", "required_targets: [cursor]\nforbidden_targets: [windsurf]\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"policy", "check", "--policy", "policy.yml", "--format", "junit"}, dir)
+	require.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, `<testsuites name="ailign" tests="2" failures="0" errors="0" skipped="0">`)
+	assert.Contains(t, stdout, `<testcase name="policy.required_targets" classname="policy"></testcase>`)
+	assert.Contains(t, stdout, `<testcase name="policy.forbidden_targets" classname="policy"></testcase>`)
+}
+
+func TestPolicyCheck_InvalidPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	writeOverlay(t, dir, "policy.yml", "max_chars: 100\n")
+
+	_, stderr, exitCode := executeCommand([]string{"policy", "check", "--policy", "policy.yml"}, dir)
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Contains(t, stderr, "parsing policy policy.yml")
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ailign/cli/internal/sync"
+	"github.com/ailign/cli/internal/target"
+	"github.com/spf13/cobra"
+)
+
+var renderTargetFlag string
+
+func newRenderCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Print the content a target's tool reads after sync",
+		Long:  "Runs the full sync pipeline for one target (overlay composition, secret scanning, link rewriting and the target's header) and prints the result to stdout without touching disk. Warnings go to stderr, so the output can be redirected or diffed as-is.",
+		Args:  cobra.NoArgs,
+		RunE:  runRender,
+	}
+	cmd.Flags().StringVarP(&renderTargetFlag, "target", "t", "",
+		"Target to render (claude, cursor, copilot, windsurf)")
+	_ = cmd.MarkFlagRequired("target")
+	return cmd
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return exitWith(ExitInvalid)
+	}
+
+	registry := target.NewDefaultRegistry()
+	tgt, ok := registry.Get(renderTargetFlag)
+	if !ok {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: unknown target %q (expected one of: %s)\n",
+			renderTargetFlag, strings.Join(registry.KnownTargets(), ", "))
+		return exitWith(ExitInvalid)
+	}
+	if !slices.Contains(cfg.Targets, tgt.Name()) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: target %s is not configured in .ailign.yml; showing what it would receive\n", tgt.Name())
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	content, warnings, err := sync.Render(cwd, cfg, tgt, cmd.Root().Version)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(syncErrorCode(err))
+	}
+
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+	}
+	_, _ = cmd.OutOrStdout().Write(content)
+	return nil
+}
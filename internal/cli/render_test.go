@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender_PrintsTargetContent(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude", "windsurf"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n\nUse gofmt.\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"render", "--target", "claude"}, dir)
+
+	assert.Equal(t, 0, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "<!-- DO NOT EDIT")
+	assert.Contains(t, stdout, "# Base\n\nUse gofmt.\n")
+	assert.Empty(t, stderr)
+
+	_, err := os.Stat(filepath.Join(dir, ".ailign"))
+	assert.True(t, os.IsNotExist(err), "render must not touch disk")
+}
+
+func TestRender_CopyModeUsesTargetFormat(t *testing.T) {
+	dir := t.TempDir()
+	writeOverlay(t, dir, ".ailign.yml", "targets:\n  - windsurf\nmode: copy\nlocal_overlays:\n  - base.md\n")
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	stdout, _, exitCode := executeCommand([]string{"render", "-t", "windsurf"}, dir)
+
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "# DO NOT EDIT")
+	assert.NotContains(t, stdout, "<!--")
+}
+
+func TestRender_UnconfiguredTarget_Warns(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"render", "--target", "cursor"}, dir)
+
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "# Base")
+	assert.Contains(t, stderr, "target cursor is not configured")
+}
+
+func TestRender_UnknownTarget_ExitNonZero(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"render", "--target", "vscode"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, `unknown target "vscode"`)
+}
+
+func TestRender_MissingTargetFlag_ExitNonZero(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+
+	stdout, _, exitCode := executeCommand([]string{"render"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+}
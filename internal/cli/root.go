@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/ailign/cli/internal/config"
 	"github.com/ailign/cli/internal/output"
@@ -13,24 +15,49 @@ import (
 
 // ErrAlreadyReported signals that the error has already been printed to stderr
 // by the command and should not be printed again by main(). main() should still
-// exit with a non-zero code.
+// exit with a non-zero code; see ExitCode.
 var ErrAlreadyReported = errors.New("error already reported")
 
 var (
 	formatFlag string
 	loadedCfg  *config.Config
+	// configWarnings holds the configuration's warnings when the format
+	// prints a single document, for the command to include in it.
+	configWarnings []config.ValidationError
 )
 
+// commandFormats lists the output formats of the commands that support
+// only some of them. The other commands accept every format.
+var commandFormats = map[string][]string{
+	"ailign stats": {"human", "json"},
+	"ailign check": {"human", "json", "github"},
+}
+
 // NewRootCommand creates the root ailign command with global flags.
 func NewRootCommand() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "ailign",
 		Short: "Instruction governance & distribution for engineering organizations",
-		Long:  "AIlign manages AI coding assistant instructions across tools and repositories.",
+		Long:  "AIlign manages AI coding assistant instructions across tools and repositories.\n\nEvery command exits 0 on success, 1 when instruction files are out of date or were edited by hand, 2 for invalid configuration, overlays or usage, 3 for I/O or internal errors, and 4 when the repository violates an organization policy.",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Annotate pull requests when running in GitHub Actions, unless
+			// --format was given
+			if !cmd.Flags().Changed("format") && os.Getenv("GITHUB_ACTIONS") == "true" {
+				formatFlag = "github"
+			}
+
 			// Validate --format flag before any work
-			if formatFlag != "human" && formatFlag != "json" {
-				return fmt.Errorf("unknown output format %q: supported formats are \"human\" and \"json\"", formatFlag)
+			switch formatFlag {
+			case "human", "json", "sarif", "junit", "github":
+			default:
+				return fmt.Errorf("unknown output format %q: supported formats are \"human\", \"json\", \"sarif\", \"junit\" and \"github\"", formatFlag)
+			}
+			if supported, ok := commandFormats[cmd.CommandPath()]; ok && !slices.Contains(supported, formatFlag) {
+				if formatFlag != "github" || cmd.Flags().Changed("format") {
+					return fmt.Errorf("%s does not support --format %s: supported formats are %s", cmd.CommandPath(), formatFlag, strings.Join(supported, ", "))
+				}
+				// GitHub Actions selected it; print what the command can
+				formatFlag = "human"
 			}
 
 			// Skip config loading for help and completion commands
@@ -41,10 +68,14 @@ func NewRootCommand() *cobra.Command {
 			if cmd.Name() == "validate" {
 				return nil
 			}
+			// Installing or removing hooks doesn't need a config
+			if cmd.Name() == "install" || cmd.Name() == "uninstall" {
+				return nil
+			}
 
 			result := loadAndValidateConfig(cmd)
 			if !result.Valid {
-				return ErrAlreadyReported
+				return exitWith(ExitInvalid)
 			}
 
 			return nil
@@ -54,10 +85,16 @@ func NewRootCommand() *cobra.Command {
 	}
 
 	rootCmd.PersistentFlags().StringVarP(&formatFlag, "format", "f", "human",
-		"Output format: human or json")
+		"Output format: human, json, sarif, junit or github (sarif and junit apply to validate, lint, policy check and sync; stats supports only human and json, check also github). Defaults to github when GITHUB_ACTIONS=true, human otherwise")
 
 	rootCmd.AddCommand(newValidateCommand())
 	rootCmd.AddCommand(newSyncCommand())
+	rootCmd.AddCommand(newLintCommand())
+	rootCmd.AddCommand(newStatsCommand())
+	rootCmd.AddCommand(newRenderCommand())
+	rootCmd.AddCommand(newHooksCommand())
+	rootCmd.AddCommand(newCheckCommand())
+	rootCmd.AddCommand(newPolicyCommand())
 
 	return rootCmd
 }
@@ -76,6 +113,22 @@ func loadAndValidateConfig(cmd *cobra.Command) *config.ValidationResult {
 	formatter := getFormatter(formatFlag)
 	outResult := toOutputResult(result, ".ailign.yml")
 
+	configWarnings = nil
+	if documentFormat(formatFlag) {
+		if !result.Valid {
+			printValidation(cmd, outResult)
+			return result
+		}
+		if reportsValidation(cmd) {
+			configWarnings = result.Warnings
+		} else if len(result.Warnings) > 0 {
+			// Keep stdout to the command's own document
+			_, _ = fmt.Fprint(cmd.ErrOrStderr(), (&output.HumanFormatter{}).FormatWarnings(outResult))
+		}
+		loadedCfg = result.Config
+		return result
+	}
+
 	if len(result.Warnings) > 0 {
 		_, _ = fmt.Fprint(cmd.ErrOrStderr(), formatter.FormatWarnings(outResult))
 	}
@@ -89,10 +142,72 @@ func loadAndValidateConfig(cmd *cobra.Command) *config.ValidationResult {
 	return result
 }
 
+// documentFormat reports whether format prints a validation result as a
+// single document holding its errors and warnings.
+func documentFormat(format string) bool {
+	return format == "json" || format == "sarif" || format == "junit"
+}
+
+// reportsValidation reports whether cmd prints a validation result, which
+// in a document format also holds the configuration's warnings.
+func reportsValidation(cmd *cobra.Command) bool {
+	switch cmd.CommandPath() {
+	case "ailign validate", "ailign lint", "ailign policy check":
+		return true
+	}
+	return false
+}
+
+// printValidation prints a validation result. The human and github
+// formats print warnings and errors to stderr and the success line to
+// stdout. Document formats print one document holding both: SARIF and
+// JUnit on stdout, JSON on stderr if the result is invalid and on stdout
+// otherwise.
+func printValidation(cmd *cobra.Command, result output.ValidationResult) {
+	formatter := getFormatter(formatFlag)
+	if documentFormat(formatFlag) {
+		if result.Valid {
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), formatter.FormatSuccess(result))
+		} else if formatFlag == "json" {
+			_, _ = fmt.Fprint(cmd.ErrOrStderr(), formatter.FormatErrors(result))
+		} else {
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), formatter.FormatErrors(result))
+		}
+		return
+	}
+
+	if len(result.Warnings) > 0 {
+		_, _ = fmt.Fprint(cmd.ErrOrStderr(), formatter.FormatWarnings(result))
+	}
+	if !result.Valid {
+		_, _ = fmt.Fprint(cmd.ErrOrStderr(), formatter.FormatErrors(result))
+		return
+	}
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), formatter.FormatSuccess(result))
+}
+
+// reportFailure reports err, which stopped cmd before it produced a
+// result, and returns an already-reported error exiting with code. The
+// error goes to stderr; a format whose consumers expect a report even
+// then (an output.FailureFormatter) also prints one on stdout.
+func reportFailure(cmd *cobra.Command, err error, code int) error {
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+	if f, ok := getFormatter(formatFlag).(output.FailureFormatter); ok {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), f.FormatFailure(err.Error()))
+	}
+	return exitWith(code)
+}
+
 func getFormatter(format string) output.Formatter {
 	switch format {
 	case "json":
 		return &output.JSONFormatter{}
+	case "sarif":
+		return &output.SARIFFormatter{}
+	case "junit":
+		return &output.JUnitFormatter{}
+	case "github":
+		return &output.GitHubFormatter{}
 	case "human":
 		return &output.HumanFormatter{}
 	default:
@@ -116,6 +231,8 @@ func toOutputResult(r *config.ValidationResult, file string) output.ValidationRe
 			Message:     e.Message,
 			Remediation: e.Remediation,
 			Severity:    e.Severity,
+			File:        e.File,
+			Line:        e.Line,
 		})
 	}
 
@@ -127,6 +244,8 @@ func toOutputResult(r *config.ValidationResult, file string) output.ValidationRe
 			Message:     w.Message,
 			Remediation: w.Remediation,
 			Severity:    w.Severity,
+			File:        w.File,
+			Line:        w.Line,
 		})
 	}
 
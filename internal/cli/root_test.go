@@ -2,23 +2,33 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	// Tests expect the human format, also when CI runs in GitHub Actions
+	_ = os.Unsetenv("GITHUB_ACTIONS")
+	os.Exit(m.Run())
+}
+
 func executeRootWithSubcommand(args []string, dir string) (stdout string, stderr string, err error) {
 	rootCmd := NewRootCommand()
 
 	// Add a no-op subcommand to trigger PersistentPreRunE
-	checkCmd := &cobra.Command{
-		Use:  "check",
+	noopCmd := &cobra.Command{
+		Use:  "noop",
 		RunE: func(cmd *cobra.Command, args []string) error { return nil },
 	}
-	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(noopCmd)
 
 	stdoutBuf := new(bytes.Buffer)
 	stderrBuf := new(bytes.Buffer)
@@ -39,7 +49,7 @@ func TestRootCommand_ValidConfig_Proceeds(t *testing.T) {
 	_ = os.WriteFile(filepath.Join(dir, ".ailign.yml"),
 		[]byte("targets:\n  - claude\n  - cursor\n"), 0644)
 
-	_, stderr, err := executeRootWithSubcommand([]string{"check"}, dir)
+	_, stderr, err := executeRootWithSubcommand([]string{"noop"}, dir)
 	assert.NoError(t, err)
 	assert.Empty(t, stderr)
 }
@@ -49,7 +59,7 @@ func TestRootCommand_WarningsDoNotBlock(t *testing.T) {
 	_ = os.WriteFile(filepath.Join(dir, ".ailign.yml"),
 		[]byte("targets:\n  - claude\ncustom_field: value\n"), 0644)
 
-	_, stderr, err := executeRootWithSubcommand([]string{"check"}, dir)
+	_, stderr, err := executeRootWithSubcommand([]string{"noop"}, dir)
 	assert.NoError(t, err)
 	assert.Contains(t, stderr, "warning")
 	assert.Contains(t, stderr, "custom_field")
@@ -97,12 +107,67 @@ func TestRootCommand_InvalidFormat_Rejected(t *testing.T) {
 	_ = os.WriteFile(filepath.Join(dir, ".ailign.yml"),
 		[]byte("targets:\n  - claude\n"), 0644)
 
-	_, _, err := executeRootWithSubcommand([]string{"--format", "yaml", "check"}, dir)
+	_, _, err := executeRootWithSubcommand([]string{"--format", "yaml", "noop"}, dir)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown output format")
 	assert.Contains(t, err.Error(), "yaml")
 }
 
+func TestRootCommand_UnsupportedFormatPerCommand(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"stats", "--format", "sarif"}, "ailign stats does not support --format sarif: supported formats are human, json"},
+		{[]string{"stats", "--format", "github"}, "ailign stats does not support --format github"},
+		{[]string{"check", "--format", "junit"}, "ailign check does not support --format junit: supported formats are human, json, github"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			// Rejected before the configuration is loaded
+			stdout := new(bytes.Buffer)
+			cmd := NewRootCommand()
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(stdout)
+			cmd.SetErr(new(bytes.Buffer))
+
+			err := cmd.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.Equal(t, ExitInvalid, ExitCode(err))
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestRootCommand_GitHubActionsStatsFallsBackToHuman(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	stdout, stderr, exitCode := executeCommand([]string{"stats"}, dir)
+	assert.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "claude (.claude/instructions.md)")
+}
+
+func TestRootCommand_GitHubActionsSelectsGitHubFormat(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, ".ailign.yml"),
+		[]byte("targets:\n  - claude\nunknown_key: 1\n"), 0644)
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	_, stderr, err := executeRootWithSubcommand([]string{"noop"}, dir)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "::warning file=.ailign.yml,line=3,title=config.unknown_field::unrecognized field")
+
+	// An explicit --format wins
+	_, stderr, err = executeRootWithSubcommand([]string{"--format", "human", "noop"}, dir)
+	assert.NoError(t, err)
+	assert.NotContains(t, stderr, "::warning")
+	assert.Contains(t, stderr, "unknown_key: unrecognized field")
+}
+
 func TestRootCommand_VersionDoesNotRequireConfig(t *testing.T) {
 	dir := t.TempDir() // No .ailign.yml
 
@@ -126,8 +191,77 @@ func TestRootCommand_VersionDoesNotRequireConfig(t *testing.T) {
 func TestRootCommand_InvalidConfig_ReturnsError(t *testing.T) {
 	dir := t.TempDir() // No .ailign.yml
 
-	_, stderr, err := executeRootWithSubcommand([]string{"check"}, dir)
+	_, stderr, err := executeRootWithSubcommand([]string{"noop"}, dir)
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, ErrAlreadyReported)
 	assert.Contains(t, stderr, "not found")
 }
+
+func TestReportFailure_FormatSARIF(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"sync with a missing overlay", []string{"sync", "--format", "sarif"}, "overlay file not found: base.md"},
+		{"policy check without a policy", []string{"policy", "check", "--format", "sarif"}, "no policy to check against"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+
+			stdout, stderr, exitCode := executeCommand(tt.args, dir)
+			assert.Equal(t, ExitInvalid, exitCode)
+			assert.Contains(t, stderr, "Error: "+tt.message)
+
+			var log struct {
+				Runs []struct {
+					Invocations []struct {
+						ExecutionSuccessful        bool `json:"executionSuccessful"`
+						ToolExecutionNotifications []struct {
+							Level   string `json:"level"`
+							Message struct {
+								Text string `json:"text"`
+							} `json:"message"`
+						} `json:"toolExecutionNotifications"`
+					} `json:"invocations"`
+				} `json:"runs"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(stdout), &log), "output must be one SARIF log: %s", stdout)
+			require.Len(t, log.Runs, 1)
+			require.Len(t, log.Runs[0].Invocations, 1)
+			invocation := log.Runs[0].Invocations[0]
+			assert.False(t, invocation.ExecutionSuccessful)
+			require.Len(t, invocation.ToolExecutionNotifications, 1)
+			assert.Equal(t, "error", invocation.ToolExecutionNotifications[0].Level)
+			assert.Contains(t, invocation.ToolExecutionNotifications[0].Message.Text, tt.message)
+		})
+	}
+}
+
+func TestReportFailure_FormatJUnit(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--format", "junit"}, dir)
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Contains(t, stderr, "Error: overlay file not found: base.md")
+
+	var report struct {
+		Errors int `xml:"errors,attr"`
+		Suites []struct {
+			Cases []struct {
+				Error *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"error"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(stdout), &report), "output must be one JUnit report: %s", stdout)
+	assert.Equal(t, 1, report.Errors)
+	require.Len(t, report.Suites, 1)
+	require.Len(t, report.Suites[0].Cases, 1)
+	require.NotNil(t, report.Suites[0].Cases[0].Error)
+	assert.Contains(t, report.Suites[0].Cases[0].Error.Message, "overlay file not found: base.md")
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ailign/cli/internal/output"
+	"github.com/ailign/cli/internal/stats"
+	"github.com/ailign/cli/internal/target"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show how much context the instructions consume per target",
+		Long:  "Reports bytes, lines and an approximate token count for the hub file and for the content each target's tool reads, broken down by source overlay and compared with the target's character limit. Only Windsurf documents one (6000 characters); claude, cursor and copilot publish none and show \"no documented limit\" unless lint.size_limits sets one. Token counts assume about four characters per token. Does not modify any files.",
+		RunE:  runStats,
+	}
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return exitWith(ExitInvalid)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(ExitError)
+	}
+
+	report, err := stats.Compute(cwd, cfg, target.NewDefaultRegistry(), cmd.Root().Version)
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+		return exitWith(syncErrorCode(err))
+	}
+
+	sf := getStatsFormatter(formatFlag)
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), sf.FormatStats(toStatsOutputResult(report)))
+	return nil
+}
+
+func getStatsFormatter(format string) output.StatsFormatter {
+	switch format {
+	case "json":
+		return &output.JSONFormatter{}
+	default:
+		return &output.HumanFormatter{}
+	}
+}
+
+// toStatsOutputResult converts a stats.Report to the output package's StatsResult.
+func toStatsOutputResult(r *stats.Report) output.StatsResult {
+	result := output.StatsResult{
+		Hub:     toStatsOutputFile(r.Hub),
+		Targets: make([]output.StatsFile, 0, len(r.Targets)),
+	}
+	for _, t := range r.Targets {
+		result.Targets = append(result.Targets, toStatsOutputFile(t))
+	}
+	return result
+}
+
+func toStatsOutputFile(f stats.FileStats) output.StatsFile {
+	file := output.StatsFile{
+		Target:       f.Target,
+		Path:         f.Path,
+		StatsFigures: output.StatsFigures(f.Figures),
+		Limit:        f.Limit,
+		Parts:        make([]output.StatsPart, 0, len(f.Parts)),
+	}
+	for _, p := range f.Parts {
+		file.Parts = append(file.Parts, output.StatsPart{Source: p.Source, StatsFigures: output.StatsFigures(p.Figures)})
+	}
+	return file
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats_HumanOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude", "windsurf"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n\nUse gofmt.\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"stats"}, dir)
+
+	assert.Equal(t, 0, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "Hub .ailign/instructions.md")
+	assert.Contains(t, stdout, "claude (.claude/instructions.md) — no documented limit (set lint.size_limits.claude to check one)")
+	assert.Contains(t, stdout, "windsurf (.windsurfrules) — limit 6000 characters")
+	assert.Contains(t, stdout, "base.md")
+}
+
+func TestStats_JSONOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"windsurf"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n\nUse gofmt.\n")
+
+	stdout, _, exitCode := executeCommand([]string{"stats", "--format", "json"}, dir)
+	require.Equal(t, 0, exitCode)
+
+	var parsed struct {
+		Hub struct {
+			Bytes int `json:"bytes"`
+		} `json:"hub"`
+		Targets []struct {
+			Target  string `json:"target"`
+			Limit   int    `json:"limit"`
+			Sources []struct {
+				Source string `json:"source"`
+			} `json:"sources"`
+		} `json:"targets"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &parsed), "stdout: %s", stdout)
+	assert.Positive(t, parsed.Hub.Bytes)
+	require.Len(t, parsed.Targets, 1)
+	assert.Equal(t, 6000, parsed.Targets[0].Limit)
+	require.Len(t, parsed.Targets[0].Sources, 2)
+	assert.Equal(t, "base.md", parsed.Targets[0].Sources[1].Source)
+}
+
+func TestStats_MissingOverlay_ExitNonZero(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"missing.md"})
+
+	stdout, stderr, exitCode := executeCommand([]string{"stats"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "missing.md")
+}
@@ -1,46 +1,113 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/gitcommit"
 	"github.com/ailign/cli/internal/output"
 	"github.com/ailign/cli/internal/sync"
 	"github.com/ailign/cli/internal/target"
 	"github.com/spf13/cobra"
 )
 
-var dryRunFlag bool
+var (
+	dryRunFlag      bool
+	atomicFlag      bool
+	syncTargetsFlag []string
+	skipTargetsFlag []string
+	lockTimeoutFlag time.Duration
+	keepOrphansFlag bool
+	watchFlag       bool
+	forceFlag       bool
+	commitFlag      bool
+)
 
 func newSyncCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Sync local instruction files to all configured targets",
-		Long:  "Composes local overlay files and creates symlinks from each target's instruction path to the central hub file (.ailign/instructions.md).",
-		RunE:  runSync,
+		Long: `Composes local overlay files and creates symlinks from each target's instruction path to the central hub file (.ailign/instructions.md unless hub.path is set). With "mode: copy", each target instead gets a rendered copy with relative links rewritten for its location and a header in its own format. Sync refuses to write anything if the composed content contains a likely secret that is not allowlisted under secrets.allowlist. Use --target and --skip-target to sync a subset of the configured targets; the hub file is always written.
+
+With --atomic, a failure in any target rolls back the hub, every target and the .gitignore/.gitattributes blocks to their state before the run. A file that cannot be snapshotted stops the run before anything is written.
+
+Concurrent runs in the same repository are serialized through a lock file at .ailign/sync.lock. --lock-timeout sets how long a run waits for it.
+
+Each run records the files it manages in .ailign/state.json; when nothing changed since the last run, sync does no work. Outputs sync created for targets that were removed from the config are deleted, unless they were edited since or --keep-orphans is given. The state and lock files are local to the working tree: .ailign/.gitignore keeps them out of git.
+
+With --watch, sync keeps running and syncs again, printing one status line per run, whenever .ailign.yml or an overlay changes. Errors are reported and watching continues.
+
+Setting commit_outputs in .ailign.yml makes sync keep an ailign block that lists the hub and target files: in .gitignore with false, in .gitattributes with true (marking them linguist-generated). Lines outside the block are never changed.
+
+If the hub or a target file was edited since the last run (including through a target symlink), sync refuses to overwrite it, names the overlay the change belongs in and exits with code 1. --force discards the edits.
+
+With --commit, sync stages exactly the hub, target and .gitignore/.gitattributes files it changed and commits them with the local git, leaving anything else already staged out of the commit. The message lists the changed files and the overlays' digests and ends with a trailer; both the subject and the trailer can be set under commit in .ailign.yml. Nothing is committed when nothing changed, or when any target failed.`,
+		RunE: runSync,
 	}
 	cmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "n", false,
 		"Preview changes without modifying any files")
+	cmd.Flags().BoolVar(&atomicFlag, "atomic", false,
+		"Apply all changes or none: roll back the hub and every target if any target fails")
+	cmd.Flags().DurationVar(&lockTimeoutFlag, "lock-timeout", sync.DefaultLockTimeout,
+		"How long to wait for another running sync to finish (0 fails at once)")
+	cmd.Flags().BoolVarP(&watchFlag, "watch", "w", false,
+		"Keep running and sync again whenever .ailign.yml or an overlay changes")
+	cmd.Flags().BoolVar(&forceFlag, "force", false,
+		"Overwrite the hub and target files even if they were edited since the last sync")
+	cmd.Flags().BoolVar(&commitFlag, "commit", false,
+		"Commit the files sync changed with git")
+	cmd.Flags().BoolVar(&keepOrphansFlag, "keep-orphans", false,
+		"Keep outputs of targets that are no longer configured instead of removing them")
+	cmd.Flags().StringSliceVarP(&syncTargetsFlag, "target", "t", nil,
+		"Sync only this configured target (repeatable)")
+	cmd.Flags().StringSliceVar(&skipTargetsFlag, "skip-target", nil,
+		"Leave this configured target untouched (repeatable)")
 	return cmd
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
 	cfg := GetConfig()
 	if cfg == nil {
-		return ErrAlreadyReported
+		return exitWith(ExitInvalid)
 	}
 
 	cwd, err := os.Getwd()
 	if err != nil {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
-		return ErrAlreadyReported
+		return reportFailure(cmd, err, ExitError)
+	}
+
+	opts := sync.SyncOptions{
+		DryRun:      dryRunFlag,
+		Targets:     syncTargetsFlag,
+		SkipTargets: skipTargetsFlag,
+		Atomic:      atomicFlag,
+		LockTimeout: lockTimeoutFlag,
+		KeepOrphans: keepOrphansFlag,
+		Force:       forceFlag,
+		Version:     cmd.Root().Version,
+	}
+	if commitFlag && (dryRunFlag || watchFlag) {
+		return reportFailure(cmd, errors.New("--commit cannot be combined with --dry-run or --watch"), ExitInvalid)
+	}
+	if watchFlag {
+		if formatFlag != "human" && formatFlag != "github" {
+			return reportFailure(cmd, errors.New("--watch prints status lines and supports only --format human or github"), ExitInvalid)
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		watchSync(ctx, cmd, cwd, cfg, opts)
+		return nil
 	}
 
 	registry := target.NewDefaultRegistry()
-	result, err := sync.Sync(cwd, cfg, registry, sync.SyncOptions{DryRun: dryRunFlag})
+	result, err := sync.Sync(cwd, cfg, registry, opts)
 	if err != nil {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
-		return ErrAlreadyReported
+		return reportFailure(cmd, err, syncErrorCode(err))
 	}
 
 	// Print warnings to stderr
@@ -48,39 +115,82 @@ func runSync(cmd *cobra.Command, args []string) error {
 		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
 	}
 
-	// Format and print result to stdout
 	syncResult := toSyncOutputResult(result, len(cfg.LocalOverlays))
+	changes, errs := findDrift(result, cwd)
+	failed := len(errs) > 0 || result.RolledBack
+
+	// Commit only a run that fully succeeded
+	var commitErr error
+	if commitFlag && !failed {
+		syncResult.Commit, commitErr = commitChanges(cwd, cfg, changes, result.Sources)
+	}
+
+	// Format and print result to stdout
 	sf := getSyncFormatter(formatFlag)
 	_, _ = fmt.Fprint(cmd.OutOrStdout(), sf.FormatSyncResult(syncResult))
 
-	// Check for per-target errors
-	for _, link := range result.Links {
-		if link.Status == "error" {
-			return ErrAlreadyReported
-		}
+	if failed {
+		return exitWith(ExitError)
+	}
+	if commitErr != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", commitErr)
+		return exitWith(ExitError)
 	}
-
 	return nil
 }
 
+// commitChanges commits the files a sync changed, as listed by
+// findDrift. It commits nothing if there are none.
+func commitChanges(dir string, cfg *config.Config, changes []output.DriftEntry, sources []sync.StateSource) (*output.CommitResult, error) {
+	if len(changes) == 0 {
+		return &output.CommitResult{}, nil
+	}
+	gitChanges := make([]gitcommit.Change, 0, len(changes))
+	for _, c := range changes {
+		gitChanges = append(gitChanges, gitcommit.Change{Path: c.Path, Status: c.Change})
+	}
+	gitSources := make([]gitcommit.Source, 0, len(sources))
+	for _, s := range sources {
+		gitSources = append(gitSources, gitcommit.Source(s))
+	}
+
+	commit, err := gitcommit.Commit(dir, cfg.CommitSubject(), gitChanges, gitSources, cfg.CommitTrailer())
+	if err != nil {
+		return nil, fmt.Errorf("committing the synced files: %w", err)
+	}
+	if commit == nil {
+		return &output.CommitResult{}, nil
+	}
+	return &output.CommitResult{SHA: commit.SHA, Subject: commit.Subject, Files: commit.Paths}, nil
+}
+
 func toSyncOutputResult(r *sync.SyncResult, overlayCount int) output.SyncResult {
 	links := make([]output.LinkResult, 0, len(r.Links))
 	for _, l := range r.Links {
 		links = append(links, output.LinkResult{
 			Target:   l.Target,
 			LinkPath: l.LinkPath,
+			Mode:     l.Mode,
 			Status:   l.Status,
 			Error:    l.Error,
 		})
 	}
 
+	gitFiles := make([]output.GitFileResult, 0, len(r.GitFiles))
+	for _, g := range r.GitFiles {
+		gitFiles = append(gitFiles, output.GitFileResult(g))
+	}
+
 	return output.SyncResult{
-		DryRun:       r.DryRun,
-		HubPath:      r.HubPath,
-		HubStatus:    r.HubStatus,
-		Links:        links,
-		Warnings:     r.Warnings,
-		OverlayCount: overlayCount,
+		DryRun:         r.DryRun,
+		HubPath:        r.HubPath,
+		HubStatus:      r.HubStatus,
+		Links:          links,
+		Warnings:       r.Warnings,
+		OverlayCount:   overlayCount,
+		RolledBack:     r.RolledBack,
+		RollbackErrors: r.RollbackErrors,
+		GitFiles:       gitFiles,
 	}
 }
 
@@ -88,6 +198,12 @@ func getSyncFormatter(format string) output.SyncFormatter {
 	switch format {
 	case "json":
 		return &output.JSONFormatter{}
+	case "sarif":
+		return &output.SARIFFormatter{}
+	case "junit":
+		return &output.JUnitFormatter{}
+	case "github":
+		return &output.GitHubFormatter{}
 	case "human":
 		return &output.HumanFormatter{}
 	default:
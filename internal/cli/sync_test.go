@@ -2,11 +2,15 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/ailign/cli/internal/sync"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -193,3 +197,212 @@ func TestSync_MissingConfig_ExitNonZero(t *testing.T) {
 	assert.NotEqual(t, 0, exitCode, "sync without config should fail")
 	assert.Contains(t, stderr, "not found")
 }
+
+// ---------------------------------------------------------------------------
+// Sync command: target filters
+// ---------------------------------------------------------------------------
+
+func TestSync_TargetFlags(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude", "cursor", "copilot"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--target", "claude", "-t", "cursor", "--format", "json"}, dir)
+	require.Equal(t, 0, exitCode, "stderr: %s", stderr)
+
+	var parsed struct {
+		Summary struct {
+			Created int `json:"created"`
+			Skipped int `json:"skipped"`
+		} `json:"summary"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &parsed))
+	assert.Equal(t, 2, parsed.Summary.Created)
+	assert.Equal(t, 1, parsed.Summary.Skipped)
+
+	_, err := os.Lstat(filepath.Join(dir, ".github", "copilot-instructions.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSync_SkipTargetFlag_HumanOutput(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude", "copilot"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+
+	stdout, _, exitCode := executeCommand([]string{"sync", "--skip-target", "copilot"}, dir)
+
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "Skipped 1 target: copilot.")
+}
+
+func TestSync_TargetFlag_NotConfigured_ExitNonZero(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--target", "cursor"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, `target "cursor": not configured`)
+}
+
+func TestSync_AtomicFlag_StagingFailure(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude", "cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+	// A directory where cursor's file belongs can't be staged, so nothing is written
+	writeOverlay(t, dir, ".cursorrules/keep.txt", "x")
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--atomic"}, dir)
+
+	assert.Equal(t, ExitError, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "Error: staging changes (nothing was written): cursor: cannot stage")
+	assert.NotContains(t, stderr, "rolled back")
+	_, err := os.Lstat(filepath.Join(dir, ".claude", "instructions.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSync_LockHeld_NamesHolder(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+
+	lock, err := sync.AcquireLock(dir, 0)
+	require.NoError(t, err)
+	defer func() { _ = lock.Release() }()
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--lock-timeout", "0s"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, fmt.Sprintf("(PID %d)", os.Getpid()))
+	assert.Contains(t, stderr, ".ailign/sync.lock")
+}
+
+func TestSync_DroppedTarget_RemovedUnlessKept(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude", "cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+	_, stderr, exitCode := executeCommand([]string{"sync"}, dir)
+	require.Equal(t, 0, exitCode, "stderr: %s", stderr)
+
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+
+	_, _, exitCode = executeCommand([]string{"sync", "--keep-orphans"}, dir)
+	require.Equal(t, 0, exitCode)
+	_, err := os.Lstat(filepath.Join(dir, ".cursorrules"))
+	require.NoError(t, err)
+
+	stdout, _, exitCode := executeCommand([]string{"sync"}, dir)
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, "Removed 1 orphaned target: cursor.")
+	_, err = os.Lstat(filepath.Join(dir, ".cursorrules"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSync_EditedHub_RefusesWithoutForce(t *testing.T) {
+	skipSyncOnWindows(t)
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	_, _, exitCode := executeCommand([]string{"sync"}, dir)
+	require.Equal(t, ExitOK, exitCode)
+
+	f, err := os.OpenFile(filepath.Join(dir, ".cursorrules"), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("Hand edit.\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, stderr, exitCode := executeCommand([]string{"sync"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	assert.Contains(t, stderr, ".ailign/instructions.md (edits to .cursorrules land here): move the change into base.md")
+
+	stdout, _, exitCode := executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitDrift, exitCode)
+	assert.Contains(t, stdout, "edited outside ailign; move the change into base.md")
+
+	_, stderr, exitCode = executeCommand([]string{"sync", "--force"}, dir)
+	assert.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	_, _, exitCode = executeCommand([]string{"check"}, dir)
+	assert.Equal(t, ExitOK, exitCode)
+}
+
+func TestSync_Commit(t *testing.T) {
+	skipSyncOnWindows(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+	git("init", "-q")
+	git("config", "user.name", "Test")
+	git("config", "user.email", "test@example.com")
+	git("config", "commit.gpgsign", "false")
+	writeConfigWithOverlays(t, dir, []string{"claude", "cursor"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	_, stderr, exitCode := executeCommand([]string{"sync", "--commit", "--dry-run"}, dir)
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Contains(t, stderr, "--commit cannot be combined")
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--commit"}, dir)
+	require.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "Committed 3 files as ")
+
+	files := git("show", "--name-only", "--format=", "HEAD")
+	assert.Equal(t, ".ailign/instructions.md\n.claude/instructions.md\n.cursorrules\n", files)
+	message := git("log", "-1", "--format=%B")
+	assert.True(t, strings.HasPrefix(message, "Sync AI instructions with ailign\n\n"), message)
+	assert.Contains(t, message, "  .cursorrules (created)\n")
+	assert.Contains(t, message, "  base.md sha256:")
+	assert.Contains(t, message, "\nGenerated-by: ailign\n")
+
+	stdout, _, exitCode = executeCommand([]string{"sync", "--commit"}, dir)
+	assert.Equal(t, ExitOK, exitCode)
+	assert.Contains(t, stdout, "Nothing to commit.\n")
+	assert.Equal(t, "1\n", git("rev-list", "--count", "HEAD"))
+}
+
+func TestSync_Commit_IgnoredOutputs(t *testing.T) {
+	skipSyncOnWindows(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+	git("init", "-q")
+	git("config", "user.name", "Test")
+	git("config", "user.email", "test@example.com")
+	git("config", "commit.gpgsign", "false")
+	writeOverlay(t, dir, ".ailign.yml", "targets:\n  - claude\n  - cursor\nlocal_overlays:\n  - base.md\ncommit_outputs: false\n")
+	writeOverlay(t, dir, "base.md", "# Base\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--commit"}, dir)
+	require.Equal(t, ExitOK, exitCode, "stderr: %s", stderr)
+	assert.Contains(t, stdout, "Committed 1 file as ")
+
+	assert.Equal(t, ".gitignore\n", git("show", "--name-only", "--format=", "HEAD"))
+	message := git("log", "-1", "--format=%B")
+	assert.Contains(t, message, "Changed:\n  .gitignore (")
+	assert.NotContains(t, message, "instructions.md")
+	assert.NotContains(t, message, ".cursorrules")
+}
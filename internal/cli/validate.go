@@ -19,11 +19,16 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	result := loadAndValidateConfig(cmd)
 	if !result.Valid {
 		// Errors already printed by loadAndValidateConfig
-		return ErrAlreadyReported
+		return exitWith(ExitInvalid)
 	}
 
-	formatter := getFormatter(formatFlag)
 	outResult := toOutputResult(result, ".ailign.yml")
+	if documentFormat(formatFlag) {
+		printValidation(cmd, outResult)
+		return nil
+	}
+	// Warnings already printed by loadAndValidateConfig
+	formatter := getFormatter(formatFlag)
 	_, _ = fmt.Fprint(cmd.OutOrStdout(), formatter.FormatSuccess(outResult))
 	return nil
 }
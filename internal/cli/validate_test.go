@@ -3,8 +3,10 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,7 @@ import (
 // executeCommand creates a fresh root command, sets the given args, and
 // executes it with the working directory changed to dir. It captures stdout
 // and stderr via cmd.SetOut/SetErr and returns the output along with an
-// exit code (0 for success, non-zero for error).
+// exit code main would exit with.
 func executeCommand(args []string, dir string) (stdout string, stderr string, exitCode int) {
 	rootCmd := NewRootCommand()
 
@@ -29,11 +31,7 @@ func executeCommand(args []string, dir string) (stdout string, stderr string, ex
 	defer func() { _ = os.Chdir(origDir) }()
 
 	err := rootCmd.Execute()
-	code := 0
-	if err != nil {
-		code = 2
-	}
-	return stdoutBuf.String(), stderrBuf.String(), code
+	return stdoutBuf.String(), stderrBuf.String(), ExitCode(err)
 }
 
 // ---------------------------------------------------------------------------
@@ -395,3 +393,98 @@ func TestValidate_EmptyFile_ExitNonZero(t *testing.T) {
 	assert.NotEqual(t, 0, exitCode, "empty config should be invalid (no targets)")
 	assert.NotEmpty(t, stderr)
 }
+
+// sarifLevels parses out as a single SARIF log and returns the level of
+// each rule's results.
+func sarifLevels(t *testing.T, out string) map[string]string {
+	t.Helper()
+	var parsed struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), "output must be one SARIF log: %s", out)
+	require.Len(t, parsed.Runs, 1)
+	levels := map[string]string{}
+	for _, r := range parsed.Runs[0].Results {
+		levels[r.RuleID] = r.Level
+	}
+	return levels
+}
+
+func TestValidate_FormatSARIF_SingleLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		wantExit int
+		want     map[string]string
+	}{
+		{"errors and warnings", "targets:\n  - notepad\nextra: true\n", ExitInvalid,
+			map[string]string{"config.targets": "error", "config.unknown_field": "warning"}},
+		{"warnings only", "targets:\n  - claude\nextra: true\n", ExitOK,
+			map[string]string{"config.unknown_field": "warning"}},
+		{"clean", "targets:\n  - claude\n", ExitOK, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ".ailign.yml"), []byte(tt.config), 0644))
+
+			stdout, stderr, exitCode := executeCommand([]string{"validate", "--format", "sarif"}, dir)
+
+			assert.Equal(t, tt.wantExit, exitCode)
+			assert.Empty(t, stderr)
+			assert.Equal(t, tt.want, sarifLevels(t, stdout))
+		})
+	}
+}
+
+func TestValidate_FormatJSON_ErrorsAndWarnings(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ailign.yml"), []byte("targets:\n  - notepad\nextra: true\n"), 0644))
+
+	stdout, stderr, exitCode := executeCommand([]string{"validate", "--format", "json"}, dir)
+
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Empty(t, stdout)
+	var result struct {
+		Errors   []json.RawMessage `json:"errors"`
+		Warnings []json.RawMessage `json:"warnings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stderr), &result), "stderr must be one JSON document: %s", stderr)
+	assert.Len(t, result.Errors, 1)
+	assert.Len(t, result.Warnings, 1)
+}
+
+func TestValidate_FormatJUnit_SingleReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ailign.yml"), []byte("targets:\n  - notepad\nextra: true\n"), 0644))
+
+	stdout, stderr, exitCode := executeCommand([]string{"validate", "--format", "junit"}, dir)
+
+	assert.Equal(t, ExitInvalid, exitCode)
+	assert.Empty(t, stderr)
+	assert.Equal(t, 1, strings.Count(stdout, "<?xml"), stdout)
+	var report struct {
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Name      string    `xml:"name,attr"`
+				Failure   *struct{} `xml:"failure"`
+				SystemOut string    `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(stdout), &report), stdout)
+	assert.Equal(t, 1, report.Failures)
+	require.Len(t, report.Suites, 1)
+	require.Len(t, report.Suites[0].Cases, 2)
+	assert.Equal(t, "config.targets", report.Suites[0].Cases[0].Name)
+	assert.NotNil(t, report.Suites[0].Cases[0].Failure)
+	assert.Equal(t, "config.unknown_field", report.Suites[0].Cases[1].Name)
+	assert.Contains(t, report.Suites[0].Cases[1].SystemOut, "warning: extra")
+}
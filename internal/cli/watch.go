@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/sync"
+	"github.com/ailign/cli/internal/target"
+	"github.com/ailign/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+// Polling settings for sync --watch. Editors often save in several
+// writes; the debounce waits for them to settle.
+var (
+	watchInterval = 200 * time.Millisecond
+	watchDebounce = 300 * time.Millisecond
+)
+
+// watchSync syncs, then syncs again whenever .ailign.yml or an overlay
+// changes, until ctx is done. The config is reloaded on every change;
+// while it is invalid the last valid config decides what is watched.
+// Each run prints one status line to stdout, and warnings and errors to
+// stderr, and no error stops watching.
+func watchSync(ctx context.Context, cmd *cobra.Command, cwd string, cfg *config.Config, opts sync.SyncOptions) {
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	cfgPath := filepath.Join(cwd, ".ailign.yml")
+	registry := target.NewDefaultRegistry()
+
+	paths := func() []string {
+		files := []string{cfgPath}
+		for _, overlay := range cfg.LocalOverlays {
+			files = append(files, filepath.Join(cwd, overlay))
+		}
+		return files
+	}
+
+	_, _ = fmt.Fprintln(out, "Watching .ailign.yml and its overlays for changes. Press Ctrl+C to stop.")
+
+	first := true
+	watch.Run(ctx, watch.Options{Interval: watchInterval, Debounce: watchDebounce}, paths, func() {
+		now := time.Now().Format("15:04:05")
+		if !first {
+			loaded := config.LoadAndValidate(cfgPath)
+			if !loaded.Valid {
+				formatter := getFormatter(formatFlag)
+				_, _ = fmt.Fprint(errOut, formatter.FormatErrors(toOutputResult(loaded, ".ailign.yml")))
+				_, _ = fmt.Fprintf(out, "%s .ailign.yml is invalid; waiting for changes\n", now)
+				return
+			}
+			cfg = loaded.Config
+		}
+		first = false
+
+		result, err := sync.Sync(cwd, cfg, registry, opts)
+		if err != nil {
+			_, _ = fmt.Fprintf(errOut, "Error: %s\n", err)
+			_, _ = fmt.Fprintf(out, "%s sync failed; waiting for changes\n", now)
+			return
+		}
+		for _, w := range result.Warnings {
+			_, _ = fmt.Fprintf(errOut, "Warning: %s\n", w)
+		}
+		for _, l := range result.Links {
+			if l.Status == "error" {
+				_, _ = fmt.Fprintf(errOut, "Error: %s: %s\n", l.LinkPath, l.Error)
+			}
+		}
+		_, _ = fmt.Fprintf(out, "%s %s\n", now, watchStatus(result))
+	})
+}
+
+// watchStatus summarizes a sync run in a few words.
+func watchStatus(r *sync.SyncResult) string {
+	counts := make(map[string]int)
+	for _, l := range r.Links {
+		counts[l.Status]++
+	}
+
+	var parts []string
+	if r.HubStatus == "written" {
+		parts = append(parts, "hub written")
+	}
+	for _, g := range r.GitFiles {
+		if g.Status == "error" {
+			counts["error"]++
+		} else {
+			parts = append(parts, filepath.Base(g.Path)+" updated")
+		}
+	}
+	for _, status := range []string{"created", "replaced", "removed", "rolled_back", "error"} {
+		if n := counts[status]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ReplaceAll(status, "_", " ")))
+		}
+	}
+	if counts["error"] > 0 {
+		return "sync failed: " + strings.Join(parts, ", ")
+	}
+	if len(parts) == 0 {
+		return "up to date"
+	}
+	return "synced: " + strings.Join(parts, ", ")
+}
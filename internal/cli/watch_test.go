@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/sync"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedBuffer is a bytes.Buffer safe to read while watchSync writes.
+type lockedBuffer struct {
+	mu  gosync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchSync_ResyncsOnChange(t *testing.T) {
+	skipSyncOnWindows(t)
+	watchInterval, watchDebounce = 10*time.Millisecond, 50*time.Millisecond
+	defer func() { watchInterval, watchDebounce = 200*time.Millisecond, 300*time.Millisecond }()
+
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "# Base\n")
+	loaded := config.LoadAndValidate(filepath.Join(dir, ".ailign.yml"))
+	require.True(t, loaded.Valid)
+
+	var stdout, stderr lockedBuffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchSync(ctx, cmd, dir, loaded.Config, sync.SyncOptions{})
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(s string, times int) {
+		t.Helper()
+		require.Eventually(t, func() bool { return strings.Count(stdout.String(), s) >= times },
+			2*time.Second, 10*time.Millisecond, "stdout: %s\nstderr: %s", stdout.String(), stderr.String())
+	}
+	waitFor("synced: hub written, 1 created", 1)
+
+	// An overlay change recomposes the hub
+	writeOverlay(t, dir, "base.md", "# Base\n\nMore.\n")
+	waitFor("synced: hub written\n", 1)
+
+	// An invalid config is reported and watching continues
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ailign.yml"), []byte("targets: [nope]\n"), 0644))
+	waitFor(".ailign.yml is invalid; waiting for changes", 1)
+
+	// A missing overlay fails the run without stopping the watch
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md", "extra.md"})
+	waitFor("sync failed; waiting for changes", 1)
+	assert.Contains(t, stderr.String(), "overlay file not found: extra.md")
+
+	// The new overlay is watched once the config names it
+	writeOverlay(t, dir, "extra.md", "# Extra\n")
+	waitFor("synced: hub written\n", 2)
+}
+
+func TestWatchStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		result sync.SyncResult
+		want   string
+	}{
+		{
+			name:   "nothing changed",
+			result: sync.SyncResult{HubStatus: "unchanged", Links: []sync.LinkResult{{Status: "exists"}}},
+			want:   "up to date",
+		},
+		{
+			name: "changes",
+			result: sync.SyncResult{HubStatus: "written", Links: []sync.LinkResult{
+				{Status: "created"}, {Status: "exists"}, {Status: "removed"},
+			}},
+			want: "synced: hub written, 1 created, 1 removed",
+		},
+		{
+			name: "errors",
+			result: sync.SyncResult{HubStatus: "rolled_back", Links: []sync.LinkResult{
+				{Status: "rolled_back"}, {Status: "error"},
+			}},
+			want: "sync failed: 1 rolled back, 1 error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, watchStatus(&tt.result))
+		})
+	}
+}
+
+func TestSync_WatchRejectsJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigWithOverlays(t, dir, []string{"claude"}, []string{"base.md"})
+	writeOverlay(t, dir, "base.md", "Content\n")
+
+	stdout, stderr, exitCode := executeCommand([]string{"sync", "--watch", "--format", "json"}, dir)
+
+	assert.NotEqual(t, 0, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "--watch")
+}
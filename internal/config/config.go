@@ -5,8 +5,154 @@ import _ "embed"
 //go:embed schema.json
 var SchemaJSON []byte
 
+// Output modes for target instruction files.
+const (
+	ModeSymlink = "symlink" // targets are symlinks to the hub file (default)
+	ModeCopy    = "copy"    // targets are rendered copies of the hub file
+)
+
+// DefaultHubPath is where the composed hub file is written unless
+// hub.path is configured.
+const DefaultHubPath = ".ailign/instructions.md"
+
 // Config represents the parsed .ailign.yml configuration file.
 type Config struct {
-	Targets       []string `yaml:"targets" json:"targets,omitempty"`
-	LocalOverlays []string `yaml:"local_overlays" json:"local_overlays,omitempty"`
+	Targets       []string        `yaml:"targets" json:"targets,omitempty"`
+	LocalOverlays []string        `yaml:"local_overlays" json:"local_overlays,omitempty"`
+	Compose       *ComposeConfig  `yaml:"compose" json:"compose,omitempty"`
+	Mode          string          `yaml:"mode" json:"mode,omitempty"`
+	CommitOutputs *bool           `yaml:"commit_outputs" json:"commit_outputs,omitempty"` // nil leaves .gitignore and .gitattributes alone
+	Commit        *CommitConfig   `yaml:"commit" json:"commit,omitempty"`
+	Policy        string          `yaml:"policy" json:"policy,omitempty"` // organization policy file, relative to the repository root
+	Hub           *HubConfig      `yaml:"hub" json:"hub,omitempty"`
+	Lint          *LintConfig     `yaml:"lint" json:"lint,omitempty"`
+	Secrets       *SecretsConfig  `yaml:"secrets" json:"secrets,omitempty"`
+	Security      *SecurityConfig `yaml:"security" json:"security,omitempty"`
+}
+
+// HubConfig controls where the hub file lives and how its managed
+// header reads.
+type HubConfig struct {
+	Path   string        `yaml:"path" json:"path,omitempty"`
+	Header *HeaderConfig `yaml:"header" json:"header,omitempty"`
+}
+
+// HeaderConfig customizes the managed-content header.
+type HeaderConfig struct {
+	Text string `yaml:"text" json:"text,omitempty"` // replaces "DO NOT EDIT — Generated by ailign"
+	Link string `yaml:"link" json:"link,omitempty"` // documentation URL shown in the header
+	// InCopies controls whether copy-mode target files carry the header.
+	// The hub file always does. Defaults to true.
+	InCopies *bool `yaml:"in_copies" json:"in_copies,omitempty"`
+}
+
+// Defaults for the commit created by "ailign sync --commit".
+const (
+	DefaultCommitSubject = "Sync AI instructions with ailign"
+	DefaultCommitTrailer = "Generated-by: ailign"
+)
+
+// CommitConfig customizes the commit created by "ailign sync --commit".
+type CommitConfig struct {
+	Subject string `yaml:"subject" json:"subject,omitempty"`
+	Trailer string `yaml:"trailer" json:"trailer,omitempty"` // "Token: value"
+}
+
+// CommitSubject returns the configured commit subject, defaulting to
+// DefaultCommitSubject.
+func (c *Config) CommitSubject() string {
+	if c.Commit == nil || c.Commit.Subject == "" {
+		return DefaultCommitSubject
+	}
+	return c.Commit.Subject
+}
+
+// CommitTrailer returns the configured commit trailer, defaulting to
+// DefaultCommitTrailer.
+func (c *Config) CommitTrailer() string {
+	if c.Commit == nil || c.Commit.Trailer == "" {
+		return DefaultCommitTrailer
+	}
+	return c.Commit.Trailer
+}
+
+// Lint rule severities. SeverityOff disables a rule.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityOff     = "off"
+)
+
+// LintConfig configures "ailign lint". Each rule field holds a severity
+// ("error", "warning" or "off"); empty means the rule's default.
+type LintConfig struct {
+	TargetSize         string         `yaml:"target_size" json:"target_size,omitempty"`
+	MissingHeading     string         `yaml:"missing_heading" json:"missing_heading,omitempty"`
+	BrokenLinks        string         `yaml:"broken_links" json:"broken_links,omitempty"`
+	TrailingWhitespace string         `yaml:"trailing_whitespace" json:"trailing_whitespace,omitempty"`
+	MixedLineEndings   string         `yaml:"mixed_line_endings" json:"mixed_line_endings,omitempty"`
+	EmptySections      string         `yaml:"empty_sections" json:"empty_sections,omitempty"`
+	LongLines          string         `yaml:"long_lines" json:"long_lines,omitempty"`
+	MaxLineLength      int            `yaml:"max_line_length" json:"max_line_length,omitempty"`
+	SizeLimits         map[string]int `yaml:"size_limits" json:"size_limits,omitempty"` // per-target character limits, overriding documented limits
+}
+
+// SecretsConfig configures secret scanning of the composed overlays.
+type SecretsConfig struct {
+	Allowlist []SecretAllowlistEntry `yaml:"allowlist" json:"allowlist,omitempty"`
+}
+
+// SecretAllowlistEntry accepts a single scan finding as a false positive.
+// Findings are identified by fingerprint so the secret itself never has
+// to be written into the config file.
+type SecretAllowlistEntry struct {
+	Fingerprint string `yaml:"fingerprint" json:"fingerprint"`
+	Reason      string `yaml:"reason" json:"reason,omitempty"`
+}
+
+// SecurityConfig sets how composition treats content that could be used
+// for prompt injection. Each field holds a severity ("error", "warning"
+// or "off"); empty means the check's default.
+type SecurityConfig struct {
+	// HiddenUnicode covers bidi controls, zero-width and tag characters.
+	// Defaults to error.
+	HiddenUnicode string `yaml:"hidden_unicode" json:"hidden_unicode,omitempty"`
+	// PromptInjection enables heuristics for suspicious instructions such
+	// as "ignore previous instructions" or exfiltration URLs. Defaults to off.
+	PromptInjection string `yaml:"prompt_injection" json:"prompt_injection,omitempty"`
+}
+
+// HubPath returns the configured hub path relative to the repository
+// root, defaulting to DefaultHubPath.
+func (c *Config) HubPath() string {
+	if c.Hub == nil || c.Hub.Path == "" {
+		return DefaultHubPath
+	}
+	return c.Hub.Path
+}
+
+// SizeLimit returns the character limit for the named target: the
+// lint.size_limits override when configured, otherwise known.
+func (c *Config) SizeLimit(name string, known int) int {
+	if c.Lint != nil {
+		if override, ok := c.Lint.SizeLimits[name]; ok {
+			return override
+		}
+	}
+	return known
+}
+
+// OutputMode returns the configured output mode, defaulting to ModeSymlink.
+func (c *Config) OutputMode() string {
+	if c.Mode == "" {
+		return ModeSymlink
+	}
+	return c.Mode
+}
+
+// ComposeConfig controls how overlays are combined into the hub file.
+type ComposeConfig struct {
+	// Dedupe drops duplicate overlays, sections and paragraphs from the
+	// composed output instead of only reporting them as warnings.
+	Dedupe bool `yaml:"dedupe" json:"dedupe"`
 }
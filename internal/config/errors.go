@@ -8,6 +8,8 @@ type ValidationError struct {
 	Message     string // human-readable error description
 	Remediation string // concrete action to fix the issue
 	Severity    string // "error" or "warning"
+	File        string // file the finding refers to, when not the config file
+	Line        int    // 1-based line in File; 0 when not applicable
 }
 
 // ValidationResult represents the outcome of validating a config file.
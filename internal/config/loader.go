@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
 )
 
 // readConfigFile reads a config file and strips the UTF-8 BOM if present.
@@ -87,5 +89,31 @@ func LoadAndValidate(path string) *ValidationResult {
 	warnings := DetectUnknownFields(data)
 	result.Warnings = append(result.Warnings, warnings...)
 
+	locateFields(data, result.Errors)
+	locateFields(data, result.Warnings)
 	return result
 }
+
+// locateFields sets Line on findings about the config file itself to
+// the line their field is on, when the field is present.
+func locateFields(data []byte, entries []ValidationError) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return
+	}
+	for i := range entries {
+		e := &entries[i]
+		if e.File != "" || e.Line != 0 || strings.HasPrefix(e.FieldPath, ".") || strings.HasPrefix(e.FieldPath, "(") {
+			continue
+		}
+		p, err := yaml.PathString("$." + e.FieldPath)
+		if err != nil {
+			continue
+		}
+		node, err := p.FilterFile(file)
+		if err != nil || node == nil || node.GetToken() == nil {
+			continue
+		}
+		e.Line = node.GetToken().Position.Line
+	}
+}
@@ -15,6 +15,15 @@ import (
 var knownSchemaProperties = map[string]bool{
 	"targets":        true,
 	"local_overlays": true,
+	"compose":        true,
+	"mode":           true,
+	"commit_outputs": true,
+	"commit":         true,
+	"policy":         true,
+	"hub":            true,
+	"lint":           true,
+	"secrets":        true,
+	"security":       true,
 }
 
 // Validate validates a Config against the embedded JSONSchema.
@@ -124,6 +133,33 @@ func marshalConfigForValidation(cfg *Config) ([]byte, error) {
 	if cfg.LocalOverlays != nil {
 		doc["local_overlays"] = cfg.LocalOverlays
 	}
+	if cfg.Compose != nil {
+		doc["compose"] = cfg.Compose
+	}
+	if cfg.Mode != "" {
+		doc["mode"] = cfg.Mode
+	}
+	if cfg.CommitOutputs != nil {
+		doc["commit_outputs"] = *cfg.CommitOutputs
+	}
+	if cfg.Commit != nil {
+		doc["commit"] = cfg.Commit
+	}
+	if cfg.Policy != "" {
+		doc["policy"] = cfg.Policy
+	}
+	if cfg.Hub != nil {
+		doc["hub"] = cfg.Hub
+	}
+	if cfg.Lint != nil {
+		doc["lint"] = cfg.Lint
+	}
+	if cfg.Secrets != nil {
+		doc["secrets"] = cfg.Secrets
+	}
+	if cfg.Security != nil {
+		doc["security"] = cfg.Security
+	}
 	return json.Marshal(doc)
 }
 
@@ -182,10 +218,20 @@ func errorToValidationError(err *jsonschema.ValidationError) *ValidationError {
 		ve.Remediation = fmt.Sprintf("Add the required field(s): %s", missing)
 
 	case *kind.Enum:
-		ve.Expected = "one of claude, cursor, copilot, windsurf"
 		ve.Actual = fmt.Sprintf("%v", k.Got)
-		ve.Message = "invalid target name"
-		ve.Remediation = "Use a supported target name: claude, cursor, copilot, windsurf"
+		if strings.HasPrefix(fieldPath, "targets") {
+			ve.Expected = "one of claude, cursor, copilot, windsurf"
+			ve.Message = "invalid target name"
+			ve.Remediation = "Use a supported target name: claude, cursor, copilot, windsurf"
+			break
+		}
+		allowed := make([]string, 0, len(k.Want))
+		for _, w := range k.Want {
+			allowed = append(allowed, fmt.Sprintf("%v", w))
+		}
+		ve.Expected = "one of " + strings.Join(allowed, ", ")
+		ve.Message = fmt.Sprintf("invalid value for %s", fieldPath)
+		ve.Remediation = fmt.Sprintf("Use one of: %s", strings.Join(allowed, ", "))
 
 	case *kind.MinItems:
 		ve.Expected = fmt.Sprintf("at least %d item(s)", k.Want)
@@ -573,3 +573,38 @@ func TestDetectUnknownFields_LocalOverlaysIsKnown(t *testing.T) {
 
 	assert.Empty(t, warnings, "local_overlays should be a known field")
 }
+
+// ---------------------------------------------------------------------------
+// mode field
+// ---------------------------------------------------------------------------
+
+func TestValidate_ModeField(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		valid bool
+	}{
+		{"unset", "", true},
+		{"symlink", "symlink", true},
+		{"copy", "copy", true},
+		{"invalid", "hardlink", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(&Config{Targets: []string{"claude"}, Mode: tt.mode})
+			assert.Equal(t, tt.valid, result.Valid)
+			if !tt.valid {
+				require.Len(t, result.Errors, 1)
+				assert.Equal(t, "mode", result.Errors[0].FieldPath)
+				assert.Equal(t, "one of symlink, copy", result.Errors[0].Expected)
+				assert.Equal(t, "hardlink", result.Errors[0].Actual)
+				assert.NotEmpty(t, result.Errors[0].Remediation)
+			}
+		})
+	}
+}
+
+func TestConfig_OutputModeDefault(t *testing.T) {
+	assert.Equal(t, ModeSymlink, (&Config{}).OutputMode())
+	assert.Equal(t, ModeCopy, (&Config{Mode: ModeCopy}).OutputMode())
+}
@@ -0,0 +1,180 @@
+// Package gitcommit commits the files a sync changed, using the git on
+// PATH, so that automation doesn't have to assemble the commit itself.
+package gitcommit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Change is a file sync changed, relative to the repository directory.
+type Change struct {
+	Path   string
+	Status string // as reported by sync: "written", "created", "replaced", "removed"
+}
+
+// Source is an overlay the changed files were composed from.
+type Source struct {
+	Path   string
+	Digest string
+}
+
+// Result describes the commit Commit created.
+type Result struct {
+	SHA     string
+	Subject string
+	Paths   []string // the files in the commit
+}
+
+// Message builds the commit message: subject, the changed files, the
+// overlays with their digests, then trailer.
+func Message(subject string, changes []Change, sources []Source, trailer string) string {
+	var b strings.Builder
+	b.WriteString(subject)
+	b.WriteString("\n\n")
+	b.WriteString("Changed:\n")
+	for _, c := range changes {
+		fmt.Fprintf(&b, "  %s (%s)\n", c.Path, c.Status)
+	}
+	if len(sources) > 0 {
+		b.WriteString("\nSources:\n")
+		for _, s := range sources {
+			fmt.Fprintf(&b, "  %s %s\n", s.Path, s.Digest)
+		}
+	}
+	if trailer != "" {
+		b.WriteString("\n")
+		b.WriteString(trailer)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Commit stages the changed files in the repository containing dir and
+// commits them, and only them, with the message Message builds from the
+// files that were staged. Files that no longer exist are removed from
+// the index; files git ignores are left out. Changes already staged for
+// other files stay staged and out of the commit. If none of the files
+// differ from HEAD, nothing is committed and Commit returns nil.
+func Commit(dir, subject string, changes []Change, sources []Source, trailer string) (*Result, error) {
+	var present, removed []string
+	for _, c := range changes {
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(c.Path))); err == nil {
+			present = append(present, c.Path)
+		} else if errors.Is(err, os.ErrNotExist) {
+			removed = append(removed, c.Path)
+		} else {
+			return nil, fmt.Errorf("committing changes: %w", err)
+		}
+	}
+
+	ignored, err := ignoredPaths(dir, present)
+	if err != nil {
+		return nil, err
+	}
+	var add []string
+	for _, p := range present {
+		if !ignored[p] {
+			add = append(add, p)
+		}
+	}
+	if len(add) > 0 {
+		if _, err := git(dir, "", append([]string{"add", "--"}, add...)...); err != nil {
+			return nil, err
+		}
+	}
+	if len(removed) > 0 {
+		if _, err := git(dir, "", append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, removed...)...); err != nil {
+			return nil, err
+		}
+	}
+
+	paths := append(add, removed...)
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	out, err := git(dir, "", append([]string{"diff", "--cached", "--name-only", "--relative", "-z", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	staged := splitNUL(out)
+	if len(staged) == 0 {
+		return nil, nil
+	}
+
+	inCommit := map[string]bool{}
+	for _, p := range staged {
+		inCommit[p] = true
+	}
+	var committed []Change
+	for _, c := range changes {
+		if inCommit[c.Path] {
+			committed = append(committed, c)
+		}
+	}
+
+	message := Message(subject, committed, sources, trailer)
+	if _, err := git(dir, message, append([]string{"commit", "--quiet", "--file=-", "--"}, staged...)...); err != nil {
+		return nil, err
+	}
+	sha, err := git(dir, "", "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return &Result{SHA: strings.TrimSpace(sha), Subject: subject, Paths: staged}, nil
+}
+
+// ignoredPaths returns the paths git ignores. Tracked files are never
+// ignored.
+func ignoredPaths(dir string, paths []string) (map[string]bool, error) {
+	ignored := map[string]bool{}
+	if len(paths) == 0 {
+		return ignored, nil
+	}
+	out, err := git(dir, strings.Join(paths, "\x00")+"\x00", "check-ignore", "--stdin", "-z")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// None of the paths are ignored
+		return ignored, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range splitNUL(out) {
+		ignored[p] = true
+	}
+	return ignored, nil
+}
+
+// git runs git in dir with stdin as its input and returns its output.
+// Errors carry git's own message.
+func git(dir, stdin string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s: %w", args[0], msg, err)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+func splitNUL(s string) []string {
+	var parts []string
+	for _, p := range strings.Split(s, "\x00") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
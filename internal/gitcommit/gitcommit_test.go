@@ -0,0 +1,112 @@
+package gitcommit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitInit(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "config", "user.name", "Test")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "commit.gpgsign", "false")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestMessage(t *testing.T) {
+	msg := Message("Sync AI instructions",
+		[]Change{{Path: ".ailign/instructions.md", Status: "written"}, {Path: ".cursorrules", Status: "removed"}},
+		[]Source{{Path: "base.md", Digest: "sha256:abc"}},
+		"Generated-by: ailign")
+	assert.Equal(t, "Sync AI instructions\n\n"+
+		"Changed:\n  .ailign/instructions.md (written)\n  .cursorrules (removed)\n\n"+
+		"Sources:\n  base.md sha256:abc\n\n"+
+		"Generated-by: ailign\n", msg)
+}
+
+func TestCommit_CommitsOnlyTheChangedFiles(t *testing.T) {
+	dir := gitInit(t)
+	writeFile(t, filepath.Join(dir, "old.md"), "old\n")
+	writeFile(t, filepath.Join(dir, "other.txt"), "v1\n")
+	runGit(t, dir, "add", "old.md", "other.txt")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+
+	// A change the user staged themselves stays out of the commit
+	writeFile(t, filepath.Join(dir, "other.txt"), "v2\n")
+	runGit(t, dir, "add", "other.txt")
+
+	writeFile(t, filepath.Join(dir, ".ailign", "instructions.md"), "# Hub\n")
+	writeFile(t, filepath.Join(dir, ".gitignore"), "/ignored.md\n")
+	writeFile(t, filepath.Join(dir, "ignored.md"), "# Hub\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "old.md")))
+
+	changes := []Change{
+		{Path: ".ailign/instructions.md", Status: "written"},
+		{Path: "ignored.md", Status: "created"},
+		{Path: "old.md", Status: "removed"},
+	}
+	result, err := Commit(dir, "Sync", changes, nil, "Generated-by: ailign")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Sync", result.Subject)
+	assert.ElementsMatch(t, []string{".ailign/instructions.md", "old.md"}, result.Paths)
+	assert.Equal(t, result.SHA, strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD")))
+
+	files := runGit(t, dir, "show", "--name-status", "--format=", "HEAD")
+	assert.Equal(t, "A\t.ailign/instructions.md\nD\told.md\n", files)
+	// The message lists only the files in the commit
+	assert.Equal(t, "Sync\n\nChanged:\n  .ailign/instructions.md (written)\n  old.md (removed)\n\nGenerated-by: ailign\n\n",
+		runGit(t, dir, "log", "-1", "--format=%B"))
+	assert.Equal(t, "M  other.txt\n", runGit(t, dir, "status", "--porcelain", "--untracked-files=no"))
+}
+
+func TestCommit_NothingToCommit(t *testing.T) {
+	dir := gitInit(t)
+	writeFile(t, filepath.Join(dir, "hub.md"), "# Hub\n")
+	runGit(t, dir, "add", "hub.md")
+	runGit(t, dir, "commit", "-q", "-m", "initial")
+
+	// Rewritten with the same content
+	result, err := Commit(dir, "Sync", []Change{{Path: "hub.md", Status: "written"}}, nil, "")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "1\n", runGit(t, dir, "rev-list", "--count", "HEAD"))
+}
+
+func TestCommit_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "hub.md"), "# Hub\n")
+
+	_, err := Commit(dir, "Sync", []Change{{Path: "hub.md", Status: "written"}}, nil, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git check-ignore")
+}
@@ -0,0 +1,210 @@
+// Package hooks installs the git pre-commit hook that stops commits
+// whose instruction files are out of date with their overlays.
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// HookName is the git hook ailign installs.
+const HookName = "pre-commit"
+
+// marker identifies a hook written by Install.
+const marker = `# Installed by "ailign hooks install"`
+
+// previousSuffix names the file an existing hook is moved to when the
+// ailign hook is installed in its place.
+const previousSuffix = ".ailign-previous"
+
+// script runs the drift check, then hands over to the hook it replaced.
+// Git runs the hook from the top of the working tree, so a repository
+// without .ailign.yml skips the check: a shared core.hooksPath directory
+// serves repositories that don't use ailign too. A missing ailign binary
+// skips the check rather than blocking commits of collaborators who
+// don't have it installed.
+const script = `#!/bin/sh
+` + marker + `; remove with "ailign hooks uninstall".
+if [ -f .ailign.yml ]; then
+	if command -v ailign >/dev/null 2>&1; then
+		ailign hooks run pre-commit || exit 1
+	else
+		echo "ailign not found on PATH; skipping instruction drift check" >&2
+	fi
+fi
+previous="$(dirname "$0")/pre-commit` + previousSuffix + `"
+if [ -x "$previous" ]; then
+	exec "$previous" "$@"
+fi
+`
+
+// Result describes what Install or Uninstall did.
+type Result struct {
+	Path     string // the pre-commit hook
+	Status   string // "installed", "updated", "exists", "removed" or "not_installed"
+	Previous string // the hook that was chained or restored, if any
+}
+
+// Dir returns the directory git runs hooks from for the repository
+// containing repoDir, honoring core.hooksPath.
+func Dir(repoDir string) (string, error) {
+	paths, err := revParse(repoDir, "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("locating git hooks directory: %w", err)
+	}
+	return paths[0], nil
+}
+
+// Shared reports whether hooksDir lies outside the repository containing
+// repoDir, as when core.hooksPath names a directory that several
+// repositories share. A hook installed there runs for all of them.
+func Shared(repoDir, hooksDir string) (bool, error) {
+	paths, err := revParse(repoDir, "--show-toplevel", "--git-common-dir")
+	if err != nil {
+		return false, fmt.Errorf("locating git repository: %w", err)
+	}
+	hooksDir = resolve(hooksDir)
+	for _, p := range paths {
+		rel, err := filepath.Rel(resolve(p), hooksDir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// revParse runs git rev-parse with args in repoDir and returns the paths
+// it prints, one per line, made absolute.
+func revParse(repoDir string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", append([]string{"rev-parse"}, args...)...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	paths := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i, p := range paths {
+		if !filepath.IsAbs(p) {
+			paths[i] = filepath.Join(repoDir, p)
+		}
+	}
+	return paths, nil
+}
+
+// Unstaged returns the paths among paths whose working tree contents
+// differ from the index of the repository containing repoDir, relative
+// to repoDir. A commit records the index, so a check of the working
+// tree only holds for the commit when these are staged.
+func Unstaged(repoDir string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	// Outside a repository git diff compares two files instead
+	if _, err := revParse(repoDir, "--git-dir"); err != nil {
+		return nil, fmt.Errorf("locating git repository: %w", err)
+	}
+	cmd := exec.Command("git", append([]string{"diff", "--name-only", "--relative", "--"}, paths...)...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("comparing the working tree with the index: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("comparing the working tree with the index: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// resolve returns p with symlinks resolved, or p itself if it can't be.
+func resolve(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return p
+}
+
+// Install writes the ailign pre-commit hook into hooksDir. An existing
+// hook that ailign didn't write is moved aside and run after the ailign
+// check, so it keeps working. A hook written by an older ailign is
+// rewritten with the current script; installing twice is a no-op.
+func Install(hooksDir string) (*Result, error) {
+	hookPath := filepath.Join(hooksDir, HookName)
+	previous := hookPath + previousSuffix
+	result := &Result{Path: hookPath, Status: "installed"}
+
+	data, err := os.ReadFile(hookPath)
+	switch {
+	case err == nil && strings.Contains(string(data), marker):
+		if _, err := os.Stat(previous); err == nil {
+			result.Previous = previous
+		}
+		if string(data) == script {
+			result.Status = "exists"
+			return result, nil
+		}
+		result.Status = "updated"
+	case err == nil:
+		if _, err := os.Lstat(previous); err == nil {
+			return nil, fmt.Errorf("cannot chain existing %s hook: %s already exists", HookName, previous)
+		}
+		if err := os.Rename(hookPath, previous); err != nil {
+			return nil, fmt.Errorf("moving existing %s hook aside: %w", HookName, err)
+		}
+		result.Previous = previous
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("reading existing %s hook: %w", HookName, err)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		return nil, fmt.Errorf("writing %s hook: %w", HookName, err)
+	}
+	// WriteFile leaves the mode of a file it didn't create untouched
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return nil, fmt.Errorf("making %s hook executable: %w", HookName, err)
+	}
+	return result, nil
+}
+
+// Uninstall removes the ailign pre-commit hook from hooksDir and puts
+// back the hook it replaced, if any. A hook ailign didn't write is
+// never touched.
+func Uninstall(hooksDir string) (*Result, error) {
+	hookPath := filepath.Join(hooksDir, HookName)
+	previous := hookPath + previousSuffix
+	result := &Result{Path: hookPath, Status: "removed"}
+
+	data, err := os.ReadFile(hookPath)
+	if errors.Is(err, os.ErrNotExist) {
+		result.Status = "not_installed"
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s hook: %w", HookName, err)
+	}
+	if !strings.Contains(string(data), marker) {
+		return nil, fmt.Errorf("%s was not installed by ailign; leaving it in place", hookPath)
+	}
+
+	if err := os.Remove(hookPath); err != nil {
+		return nil, fmt.Errorf("removing %s hook: %w", HookName, err)
+	}
+	if _, err := os.Lstat(previous); err == nil {
+		if err := os.Rename(previous, hookPath); err != nil {
+			return nil, fmt.Errorf("restoring previous %s hook: %w", HookName, err)
+		}
+		result.Previous = hookPath
+	}
+	return result, nil
+}
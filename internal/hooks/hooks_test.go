@@ -0,0 +1,264 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitInit(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q", dir)
+	require.NoError(t, cmd.Run())
+	resolved, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	return resolved
+}
+
+func gitConfig(t *testing.T, dir, key, value string) {
+	t.Helper()
+	cmd := exec.Command("git", "config", key, value)
+	cmd.Dir = dir
+	require.NoError(t, cmd.Run())
+}
+
+func TestDir(t *testing.T) {
+	repo := gitInit(t)
+
+	dir, err := Dir(repo)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, ".git", "hooks"), dir)
+
+	gitConfig(t, repo, "core.hooksPath", ".husky")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "sub"), 0755))
+	dir, err = Dir(filepath.Join(repo, "sub"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, ".husky"), dir)
+}
+
+func TestShared(t *testing.T) {
+	repo := gitInit(t)
+
+	tests := []struct {
+		name      string
+		hooksPath string
+		want      bool
+	}{
+		{"default", "", false},
+		{"inside the repository", ".husky", false},
+		{"outside the repository", filepath.Join(t.TempDir(), "hooks"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.hooksPath != "" {
+				gitConfig(t, repo, "core.hooksPath", tt.hooksPath)
+			}
+			dir, err := Dir(repo)
+			require.NoError(t, err)
+
+			shared, err := Shared(repo, dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, shared)
+		})
+	}
+}
+
+func TestUnstaged(t *testing.T) {
+	repo := gitInit(t)
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "CLAUDE.md"), []byte("# One\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "other.md"), []byte("# One\n"), 0644))
+	cmd := exec.Command("git", "add", ".")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+
+	paths := []string{"CLAUDE.md", filepath.Join(repo, ".cursorrules")}
+	unstaged, err := Unstaged(repo, paths)
+	require.NoError(t, err)
+	assert.Empty(t, unstaged)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "CLAUDE.md"), []byte("# Two\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "other.md"), []byte("# Two\n"), 0644))
+	unstaged, err = Unstaged(repo, paths)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CLAUDE.md"}, unstaged)
+}
+
+func TestUnstaged_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	_, err := Unstaged(t.TempDir(), []string{"CLAUDE.md"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locating git repository")
+}
+
+func TestDir_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	_, err := Dir(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locating git hooks directory")
+}
+
+func TestInstall_Fresh(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+
+	result, err := Install(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "installed", result.Status)
+	assert.Empty(t, result.Previous)
+
+	data, err := os.ReadFile(filepath.Join(dir, "pre-commit"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), marker)
+	assert.Contains(t, string(data), "ailign hooks run pre-commit")
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dir, "pre-commit"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	}
+
+	again, err := Install(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "exists", again.Status)
+}
+
+func TestInstall_UpdatesOutdatedHook(t *testing.T) {
+	dir := t.TempDir()
+	hook := filepath.Join(dir, "pre-commit")
+	outdated := "#!/bin/sh\n" + marker + "\nailign hooks run pre-commit || exit 1\n"
+	require.NoError(t, os.WriteFile(hook, []byte(outdated), 0755))
+
+	result, err := Install(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", result.Status)
+	assert.Empty(t, result.Previous)
+
+	data, err := os.ReadFile(hook)
+	require.NoError(t, err)
+	assert.Equal(t, script, string(data))
+	_, err = os.Stat(hook + previousSuffix)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInstall_ChainsExistingHook(t *testing.T) {
+	dir := t.TempDir()
+	hook := filepath.Join(dir, "pre-commit")
+	require.NoError(t, os.WriteFile(hook, []byte("#!/bin/sh\nexit 0\n"), 0755))
+
+	result, err := Install(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "installed", result.Status)
+	assert.Equal(t, hook+".ailign-previous", result.Previous)
+
+	previous, err := os.ReadFile(result.Previous)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nexit 0\n", string(previous))
+
+	// Uninstalling restores the original hook
+	removed, err := Uninstall(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "removed", removed.Status)
+	restored, err := os.ReadFile(hook)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nexit 0\n", string(restored))
+	_, err = os.Stat(hook + ".ailign-previous")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestInstall_RefusesToOverwritePrevious(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pre-commit"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pre-commit.ailign-previous"), []byte("#!/bin/sh\n"), 0755))
+
+	_, err := Install(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestUninstall(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    string // existing pre-commit content; empty for none
+		status  string
+		wantErr string
+	}{
+		{name: "not installed", status: "not_installed"},
+		{name: "ours", hook: script, status: "removed"},
+		{name: "someone else's", hook: "#!/bin/sh\nnpm test\n", wantErr: "was not installed by ailign"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.hook != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "pre-commit"), []byte(tt.hook), 0755))
+			}
+
+			result, err := Uninstall(dir)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, result.Status)
+			_, err = os.Stat(filepath.Join(dir, "pre-commit"))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestScript_RunsPreviousHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	ran := filepath.Join(dir, "ran")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pre-commit"),
+		[]byte("#!/bin/sh\necho \"$1\" > "+ran+"\n"), 0755))
+	_, err := Install(dir)
+	require.NoError(t, err)
+
+	// Without ailign on PATH the check is skipped and the chain continues
+	work := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(work, ".ailign.yml"), []byte("targets: [claude]\n"), 0644))
+	cmd := exec.Command("/bin/sh", filepath.Join(dir, "pre-commit"), "arg")
+	cmd.Dir = work
+	cmd.Env = []string{"PATH=/usr/bin:/bin"}
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "output: %s", out)
+	assert.Contains(t, string(out), "ailign not found on PATH")
+
+	data, err := os.ReadFile(ran)
+	require.NoError(t, err)
+	assert.Equal(t, "arg\n", string(data))
+}
+
+func TestScript_SkipsRepositoriesWithoutConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	_, err := Install(dir)
+	require.NoError(t, err)
+
+	// An ailign that would fail the check must not be run
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ailign"), []byte("#!/bin/sh\necho ran >&2\nexit 2\n"), 0755))
+	cmd := exec.Command("/bin/sh", filepath.Join(dir, "pre-commit"))
+	cmd.Dir = t.TempDir()
+	cmd.Env = []string{"PATH=" + bin + ":/usr/bin:/bin"}
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "output: %s", out)
+	assert.Empty(t, string(out))
+}
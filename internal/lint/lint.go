@@ -0,0 +1,330 @@
+// Package lint checks the content of overlay files and the rendered
+// target output for quality issues that schema validation can't catch.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/markdown"
+	"github.com/ailign/cli/internal/sync"
+	"github.com/ailign/cli/internal/target"
+)
+
+// Rule names. Each is also the key under "lint:" in .ailign.yml that
+// sets the rule's severity.
+const (
+	RuleTargetSize         = "target_size"
+	RuleMissingHeading     = "missing_heading"
+	RuleBrokenLinks        = "broken_links"
+	RuleTrailingWhitespace = "trailing_whitespace"
+	RuleMixedLineEndings   = "mixed_line_endings"
+	RuleEmptySections      = "empty_sections"
+	RuleLongLines          = "long_lines"
+)
+
+// DefaultMaxLineLength is the long_lines threshold when max_line_length
+// is not configured.
+const DefaultMaxLineLength = 300
+
+// rules lists every rule, in the order they are reported.
+var rules = []string{
+	RuleTargetSize,
+	RuleMissingHeading,
+	RuleBrokenLinks,
+	RuleTrailingWhitespace,
+	RuleMixedLineEndings,
+	RuleEmptySections,
+	RuleLongLines,
+}
+
+// defaultSeverity is each rule's severity when not configured.
+var defaultSeverity = map[string]string{
+	RuleTargetSize:         config.SeverityError,
+	RuleMissingHeading:     config.SeverityWarning,
+	RuleBrokenLinks:        config.SeverityWarning,
+	RuleTrailingWhitespace: config.SeverityWarning,
+	RuleMixedLineEndings:   config.SeverityWarning,
+	RuleEmptySections:      config.SeverityWarning,
+	RuleLongLines:          config.SeverityWarning,
+}
+
+var directiveLine = regexp.MustCompile(`^[ \t]*<!--[ \t]*ailign:`)
+
+// Result holds lint findings split by severity.
+type Result struct {
+	Errors   []config.ValidationError
+	Warnings []config.ValidationError
+	// Rules lists the enabled rules, by their field path ("lint.<rule>").
+	Rules []string
+}
+
+// linter accumulates findings for a single run.
+type linter struct {
+	cfg    *config.Config
+	result *Result
+}
+
+// Lint runs every enabled rule against the configured overlays and the
+// content each target would receive from sync. Nothing is written.
+// All findings are collected; Lint never stops at the first one.
+// version is the ailign version stamped into managed headers.
+func Lint(baseDir string, cfg *config.Config, registry *target.Registry, version string) *Result {
+	l := &linter{cfg: cfg, result: &Result{}}
+	for _, rule := range rules {
+		if l.enabled(rule) {
+			l.result.Rules = append(l.result.Rules, "lint."+rule)
+		}
+	}
+
+	for _, overlay := range cfg.LocalOverlays {
+		// Paths that escape baseDir, unreadable files and invalid content
+		// are reported below through the composition error.
+		if sync.ValidateOverlayPath(baseDir, overlay) != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(baseDir, overlay))
+		if err != nil || !utf8.Valid(data) {
+			continue
+		}
+		l.lintOverlay(baseDir, overlay, string(data))
+	}
+
+	composed, err := sync.Compose(baseDir, cfg, version)
+	if err != nil {
+		for _, msg := range strings.Split(err.Error(), "\n") {
+			l.result.Errors = append(l.result.Errors, config.ValidationError{
+				FieldPath:   "local_overlays",
+				Expected:    "overlays that compose without errors",
+				Actual:      msg,
+				Message:     "overlays cannot be composed",
+				Remediation: "Fix the overlay error, then rerun \"ailign lint\"",
+				Severity:    config.SeverityError,
+			})
+		}
+		return l.result
+	}
+
+	l.lintTargetSizes(composed, registry)
+	return l.result
+}
+
+// severity returns the configured severity for rule.
+func (l *linter) severity(rule string) string {
+	var configured string
+	if lc := l.cfg.Lint; lc != nil {
+		switch rule {
+		case RuleTargetSize:
+			configured = lc.TargetSize
+		case RuleMissingHeading:
+			configured = lc.MissingHeading
+		case RuleBrokenLinks:
+			configured = lc.BrokenLinks
+		case RuleTrailingWhitespace:
+			configured = lc.TrailingWhitespace
+		case RuleMixedLineEndings:
+			configured = lc.MixedLineEndings
+		case RuleEmptySections:
+			configured = lc.EmptySections
+		case RuleLongLines:
+			configured = lc.LongLines
+		}
+	}
+	if configured == "" {
+		return defaultSeverity[rule]
+	}
+	return configured
+}
+
+func (l *linter) enabled(rule string) bool {
+	return l.severity(rule) != config.SeverityOff
+}
+
+// report records a finding for rule at file:line.
+func (l *linter) report(rule, file string, line int, message, expected, actual, remediation string) {
+	sev := l.severity(rule)
+	finding := config.ValidationError{
+		FieldPath:   "lint." + rule,
+		Expected:    expected,
+		Actual:      actual,
+		Message:     message,
+		Remediation: remediation,
+		Severity:    sev,
+		File:        file,
+		Line:        line,
+	}
+	if sev == config.SeverityError {
+		l.result.Errors = append(l.result.Errors, finding)
+	} else {
+		l.result.Warnings = append(l.result.Warnings, finding)
+	}
+}
+
+// lintOverlay runs the per-file rules against one overlay.
+func (l *linter) lintOverlay(baseDir, overlay, content string) {
+	lines := markdown.SplitLines(content)
+	maxLen := DefaultMaxLineLength
+	if l.cfg.Lint != nil && l.cfg.Lint.MaxLineLength > 0 {
+		maxLen = l.cfg.Lint.MaxLineLength
+	}
+
+	type heading struct {
+		level, line int
+		directive   bool
+	}
+	var headings []heading
+	var fences markdown.Fences
+	hasDirectives := false
+	crlf, lf := 0, 0
+	// bodyAfter[i] is true when non-blank content follows heading i
+	// before the next heading.
+	var bodyAfter []bool
+
+	for i, raw := range lines {
+		lineNo := i + 1
+		text := strings.TrimRight(raw, "\r\n")
+		if strings.HasSuffix(raw, "\r\n") {
+			crlf++
+		} else if strings.HasSuffix(raw, "\n") {
+			lf++
+		}
+
+		if l.enabled(RuleTrailingWhitespace) && text != strings.TrimRight(text, " \t") {
+			n := len(text) - len(strings.TrimRight(text, " \t"))
+			l.report(RuleTrailingWhitespace, overlay, lineNo,
+				"line has trailing whitespace",
+				"no trailing whitespace", fmt.Sprintf("%d trailing whitespace character(s)", n),
+				"Remove the trailing spaces or tabs")
+		}
+
+		if fences.Next(text) {
+			if len(bodyAfter) > 0 {
+				bodyAfter[len(bodyAfter)-1] = true
+			}
+			continue
+		}
+
+		if l.enabled(RuleLongLines) {
+			if n := utf8.RuneCountInString(text); n > maxLen {
+				l.report(RuleLongLines, overlay, lineNo,
+					"line is too long",
+					fmt.Sprintf("at most %d characters", maxLen), fmt.Sprintf("%d characters", n),
+					"Wrap the line, or raise lint.max_line_length")
+			}
+		}
+
+		if directiveLine.MatchString(text) {
+			hasDirectives = true
+			continue
+		}
+		if level, _, ok := markdown.ParseHeading(text); ok {
+			prevDirective := i > 0 && directiveLine.MatchString(lines[i-1])
+			headings = append(headings, heading{level: level, line: lineNo, directive: prevDirective})
+			bodyAfter = append(bodyAfter, false)
+			continue
+		}
+		if strings.TrimSpace(text) != "" && len(bodyAfter) > 0 {
+			bodyAfter[len(bodyAfter)-1] = true
+		}
+	}
+
+	if l.enabled(RuleMixedLineEndings) && crlf > 0 && lf > 0 {
+		// Point at the first line whose ending differs from the file's first line.
+		firstCRLF := strings.HasSuffix(lines[0], "\r\n")
+		first := 0
+		for i, raw := range lines {
+			if strings.HasSuffix(raw, "\n") && strings.HasSuffix(raw, "\r\n") != firstCRLF {
+				first = i + 1
+				break
+			}
+		}
+		l.report(RuleMixedLineEndings, overlay, first,
+			"file mixes CRLF and LF line endings",
+			"one line ending style", fmt.Sprintf("%d CRLF, %d LF", crlf, lf),
+			"Convert the file to LF line endings")
+	}
+
+	if l.enabled(RuleMissingHeading) && !hasDirectives && strings.TrimSpace(content) != "" {
+		found := false
+		for _, h := range headings {
+			if h.level == 1 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l.report(RuleMissingHeading, overlay, 1,
+				"overlay has no top-level heading",
+				"a level-1 (#) heading", "",
+				"Start the overlay with a \"# Title\" heading describing its scope")
+		}
+	}
+
+	if l.enabled(RuleEmptySections) {
+		for i, h := range headings {
+			if bodyAfter[i] || h.directive {
+				continue
+			}
+			if i+1 < len(headings) && headings[i+1].level > h.level {
+				continue // has subsections
+			}
+			l.report(RuleEmptySections, overlay, h.line,
+				"section is empty",
+				"content under the heading", "",
+				"Add content to the section or remove the heading")
+		}
+	}
+
+	if l.enabled(RuleBrokenLinks) {
+		overlayDir := path.Dir(filepath.ToSlash(filepath.Clean(overlay)))
+		for _, link := range markdown.RelativeLinks(content) {
+			p, _ := markdown.SplitLinkDest(link.Dest)
+			if p == "" {
+				continue
+			}
+			if decoded, err := url.PathUnescape(p); err == nil {
+				p = decoded
+			}
+			resolved := path.Join(overlayDir, p)
+			if _, err := os.Stat(filepath.Join(baseDir, filepath.FromSlash(resolved))); errors.Is(err, os.ErrNotExist) {
+				l.report(RuleBrokenLinks, overlay, link.Line,
+					"link target does not exist",
+					"an existing file", resolved,
+					"Fix the link path (relative to the overlay file) or remove the link")
+			}
+		}
+	}
+}
+
+// lintTargetSizes compares each target's rendered content with the
+// tool's size limit.
+func (l *linter) lintTargetSizes(composed *sync.ComposeResult, registry *target.Registry) {
+	if !l.enabled(RuleTargetSize) {
+		return
+	}
+	for _, name := range l.cfg.Targets {
+		tgt, ok := registry.Get(name)
+		if !ok {
+			continue
+		}
+		limit := l.cfg.SizeLimit(name, target.CharLimit(tgt))
+		if limit <= 0 {
+			continue
+		}
+		n := utf8.RuneCount(sync.TargetContent(composed, l.cfg, tgt))
+		if n > limit {
+			l.report(RuleTargetSize, tgt.InstructionPath(), 0,
+				fmt.Sprintf("%s instructions exceed the tool's size limit", name),
+				fmt.Sprintf("at most %d characters", limit), fmt.Sprintf("%d characters", n),
+				"Shorten the overlays, or move rarely needed guidance into linked documents")
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func run(t *testing.T, dir string, cfg *config.Config) *Result {
+	t.Helper()
+	return Lint(dir, cfg, target.NewDefaultRegistry(), "")
+}
+
+// findings returns the field paths of all findings, errors first.
+func findings(r *Result) []string {
+	var out []string
+	for _, e := range r.Errors {
+		out = append(out, e.FieldPath)
+	}
+	for _, w := range r.Warnings {
+		out = append(out, w.FieldPath)
+	}
+	return out
+}
+
+func TestLint_CleanOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse gofmt.\n")
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md"}})
+	assert.Empty(t, r.Errors)
+	assert.Empty(t, r.Warnings)
+}
+
+func TestLint_ListsEnabledRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md"}, Lint: &config.LintConfig{LongLines: config.SeverityOff}}
+
+	r := run(t, dir, cfg)
+	assert.Equal(t, []string{
+		"lint.target_size", "lint.missing_heading", "lint.broken_links", "lint.trailing_whitespace",
+		"lint.mixed_line_endings", "lint.empty_sections",
+	}, r.Rules)
+}
+
+func TestLint_OverlayRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		rule    string
+		line    int
+	}{
+		{"missing heading", "Just text.\n", RuleMissingHeading, 1},
+		{"trailing whitespace", "# A\n\ntext  \n", RuleTrailingWhitespace, 3},
+		{"mixed line endings", "# A\r\n\r\ntext\n", RuleMixedLineEndings, 3},
+		{"empty section", "# A\n\ntext\n\n## Empty\n\n## Full\n\ntext\n", RuleEmptySections, 5},
+		{"broken link", "# A\n\nSee [x](missing.md).\n", RuleBrokenLinks, 3},
+		{"long line", "# A\n\n" + strings.Repeat("x", 301) + "\n", RuleLongLines, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "a.md"), tt.content)
+
+			r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"a.md"}})
+			assert.Empty(t, r.Errors)
+			require.Len(t, r.Warnings, 1, "findings: %v", findings(r))
+			w := r.Warnings[0]
+			assert.Equal(t, "lint."+tt.rule, w.FieldPath)
+			assert.Equal(t, "a.md", w.File)
+			assert.Equal(t, tt.line, w.Line)
+			assert.Equal(t, config.SeverityWarning, w.Severity)
+			assert.NotEmpty(t, w.Remediation)
+		})
+	}
+}
+
+func TestLint_IgnoresFencedContent(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\n```md\n## Empty\n[x](missing.md)\n```\n")
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"a.md"}})
+	assert.Empty(t, findings(r))
+}
+
+func TestLint_ParentSectionNotEmpty(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n## B\n\ntext\n")
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"a.md"}})
+	assert.Empty(t, findings(r))
+}
+
+func TestLint_DirectiveOverlaysSkipHeadingRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\n## Testing\n\nUse mocks.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:delete -->\n## Testing\n")
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md", "repo.md"}})
+	assert.Empty(t, findings(r))
+}
+
+func TestLint_SeverityConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "Just text.  \n")
+
+	r := run(t, dir, &config.Config{
+		Targets:       []string{"claude"},
+		LocalOverlays: []string{"a.md"},
+		Lint: &config.LintConfig{
+			MissingHeading:     config.SeverityError,
+			TrailingWhitespace: config.SeverityOff,
+		},
+	})
+	require.Len(t, r.Errors, 1)
+	assert.Equal(t, "lint."+RuleMissingHeading, r.Errors[0].FieldPath)
+	assert.Equal(t, config.SeverityError, r.Errors[0].Severity)
+	assert.Empty(t, r.Warnings)
+}
+
+func TestLint_MaxLineLength(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\nThis line is longer than twenty.\n")
+
+	r := run(t, dir, &config.Config{
+		Targets:       []string{"claude"},
+		LocalOverlays: []string{"a.md"},
+		Lint:          &config.LintConfig{MaxLineLength: 20},
+	})
+	require.Len(t, r.Warnings, 1)
+	assert.Equal(t, "at most 20 characters", r.Warnings[0].Expected)
+}
+
+func TestLint_TargetSize(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\n"+strings.Repeat("Keep it short.\n", 500))
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude", "windsurf"}, LocalOverlays: []string{"a.md"}})
+	require.Len(t, r.Errors, 1)
+	assert.Equal(t, "lint."+RuleTargetSize, r.Errors[0].FieldPath)
+	assert.Equal(t, ".windsurfrules", r.Errors[0].File)
+	assert.Equal(t, "at most 6000 characters", r.Errors[0].Expected)
+}
+
+func TestLint_TargetSizeOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\n"+strings.Repeat("Keep it short.\n", 20))
+
+	r := run(t, dir, &config.Config{
+		Targets:       []string{"claude"},
+		LocalOverlays: []string{"a.md"},
+		Lint:          &config.LintConfig{SizeLimits: map[string]int{"claude": 100}},
+	})
+	require.Len(t, r.Errors, 1)
+	assert.Equal(t, "lint."+RuleTargetSize, r.Errors[0].FieldPath)
+	assert.Equal(t, ".claude/instructions.md", r.Errors[0].File)
+}
+
+func TestLint_ComposeErrorReported(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\ntext\n")
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"a.md", "missing.md"}})
+	require.Len(t, r.Errors, 1)
+	assert.Equal(t, "local_overlays", r.Errors[0].FieldPath)
+	assert.Contains(t, r.Errors[0].Actual, "missing.md")
+}
+
+func TestLint_OverlayOutsideBaseDirNotRead(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "repo")
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\ntext\n")
+	writeFile(t, filepath.Join(root, "secret.md"), "secret  \n")
+
+	r := run(t, dir, &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"a.md", "../secret.md"}})
+	assert.Empty(t, r.Warnings)
+	require.Len(t, r.Errors, 1)
+	assert.Equal(t, "local_overlays", r.Errors[0].FieldPath)
+	assert.Equal(t, config.SeverityError, r.Errors[0].Severity)
+	assert.Contains(t, r.Errors[0].Actual, "overlay path traversal rejected: ../secret.md")
+}
@@ -0,0 +1,109 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// inlineLinkPattern matches the destination of an inline link or image:
+	// "](dest)", "](dest "title")" or "](<dest>)".
+	inlineLinkPattern = regexp.MustCompile(`\]\([ \t]*(<[^>\n]*>|[^)\s]+)`)
+	// refDefinitionPattern matches a link reference definition: "[id]: dest".
+	refDefinitionPattern = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]*(<[^>\n]*>|\S+)`)
+	schemePattern        = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+)
+
+// Link is a relative link destination found in Markdown text.
+type Link struct {
+	Start, End int    // byte range of the destination in the text
+	Dest       string // destination without angle brackets
+	Angled     bool   // destination was written as <dest>
+	Line       int    // 1-based line within the text
+}
+
+// RelativeLinks returns relative link and image destinations in content,
+// skipping fenced code blocks and inline code spans.
+func RelativeLinks(content string) []Link {
+	var links []Link
+	var fences Fences
+	offset := 0
+
+	for i, line := range SplitLines(content) {
+		trimmed := strings.TrimRight(line, "\r\n")
+		lineStart := offset
+		offset += len(line)
+
+		if fences.Next(trimmed) {
+			continue
+		}
+
+		masked := maskCodeSpans(trimmed)
+		var matches [][]int
+		if m := refDefinitionPattern.FindStringSubmatchIndex(masked); m != nil {
+			matches = append(matches, m)
+		}
+		matches = append(matches, inlineLinkPattern.FindAllStringSubmatchIndex(masked, -1)...)
+
+		for _, m := range matches {
+			raw := trimmed[m[2]:m[3]]
+			link := Link{Start: lineStart + m[2], End: lineStart + m[3], Dest: raw, Line: i + 1}
+			if strings.HasPrefix(raw, "<") {
+				link.Dest = strings.TrimSuffix(strings.TrimPrefix(raw, "<"), ">")
+				link.Angled = true
+			}
+			if IsRelativeLink(link.Dest) {
+				links = append(links, link)
+			}
+		}
+	}
+	return links
+}
+
+// maskCodeSpans replaces the contents of inline code spans with spaces so
+// link patterns don't match inside them. Offsets are preserved.
+func maskCodeSpans(line string) string {
+	if !strings.Contains(line, "`") {
+		return line
+	}
+	b := []byte(line)
+	for i := 0; i < len(b); {
+		if b[i] != '`' {
+			i++
+			continue
+		}
+		n := 0
+		for i+n < len(b) && b[i+n] == '`' {
+			n++
+		}
+		closer := strings.Index(line[i+n:], strings.Repeat("`", n))
+		if closer < 0 {
+			i += n
+			continue
+		}
+		end := i + n + closer + n
+		for j := i; j < end; j++ {
+			b[j] = ' '
+		}
+		i = end
+	}
+	return string(b)
+}
+
+// IsRelativeLink reports whether dest is a relative path that depends on
+// the location of the file it appears in.
+func IsRelativeLink(dest string) bool {
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") || strings.HasPrefix(dest, "\\") {
+		return false
+	}
+	return !schemePattern.MatchString(dest)
+}
+
+// SplitLinkDest separates the path of a link destination from its query
+// string and fragment.
+func SplitLinkDest(dest string) (p, suffix string) {
+	if i := strings.IndexAny(dest, "?#"); i >= 0 {
+		return dest[:i], dest[i:]
+	}
+	return dest, ""
+}
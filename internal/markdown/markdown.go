@@ -0,0 +1,99 @@
+// Package markdown provides the small subset of Markdown parsing ailign
+// needs: line splitting, fenced code blocks, ATX headings and relative
+// link destinations. It works on raw text so callers can report exact
+// line numbers and rewrite content in place.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*$`)
+	closingHashes  = regexp.MustCompile(`(?:^|[ \t]+)#+$`)
+)
+
+// SplitLines splits s into lines, keeping line terminators.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// ParseHeading reports whether line is an ATX heading and returns its
+// level and normalized text.
+func ParseHeading(line string) (int, string, bool) {
+	m := headingPattern.FindStringSubmatch(line)
+	if m == nil {
+		return 0, "", false
+	}
+	title := closingHashes.ReplaceAllString(m[2], "")
+	return len(m[1]), NormalizeTitle(title), true
+}
+
+// NormalizeTitle trims and collapses internal whitespace in heading text.
+func NormalizeTitle(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// FenceOpener returns the fence marker if line opens a fenced code block.
+func FenceOpener(line string) string {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 {
+		return ""
+	}
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(s) && s[n] == c {
+			n++
+		}
+		if n >= 3 {
+			if c == '`' && strings.ContainsRune(s[n:], '`') {
+				return ""
+			}
+			return s[:n]
+		}
+	}
+	return ""
+}
+
+// IsFenceClose reports whether line closes a fence opened with marker.
+func IsFenceClose(line, marker string) bool {
+	s := strings.TrimSpace(line)
+	if !strings.HasPrefix(s, marker) {
+		return false
+	}
+	return strings.Trim(s, marker[:1]) == ""
+}
+
+// Fences tracks fenced code blocks while scanning lines in order.
+type Fences struct {
+	marker string
+}
+
+// Next consumes a line (without terminator) and reports whether it is
+// part of a fenced code block, including the opening and closing fences.
+func (f *Fences) Next(line string) bool {
+	if f.marker != "" {
+		if IsFenceClose(line, f.marker) {
+			f.marker = ""
+		}
+		return true
+	}
+	if m := FenceOpener(line); m != "" {
+		f.marker = m
+		return true
+	}
+	return false
+}
+
+// Open reports whether a fenced code block is currently open.
+func (f *Fences) Open() bool {
+	return f.marker != ""
+}
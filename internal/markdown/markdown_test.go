@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeading(t *testing.T) {
+	tests := []struct {
+		line  string
+		level int
+		title string
+		ok    bool
+	}{
+		{"# Title", 1, "Title", true},
+		{"###   Code   Style  ##", 3, "Code Style", true},
+		{"   ## Indented", 2, "Indented", true},
+		{"    # Code block", 0, "", false},
+		{"#NoSpace", 0, "", false},
+		{"####### Too deep", 0, "", false},
+		{"plain text", 0, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			level, title, ok := ParseHeading(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.level, level)
+			assert.Equal(t, tt.title, title)
+		})
+	}
+}
+
+func TestFences_TracksOpenFence(t *testing.T) {
+	var f Fences
+	lines := []string{"text", "```go", "# not a heading", "~~~", "```", "after"}
+	var inside []bool
+	for _, l := range lines {
+		inside = append(inside, f.Next(l))
+	}
+	assert.Equal(t, []bool{false, true, true, true, true, false}, inside)
+	assert.False(t, f.Open())
+}
+
+func TestRelativeLinks(t *testing.T) {
+	content := "See [docs](docs/a.md) and [site](https://example.com).\n" +
+		"Inline `[x](code.md)` is ignored.\n" +
+		"```\n[y](fenced.md)\n```\n" +
+		"[ref]: <../b.md#top>\n" +
+		"[anchor](#local)\n"
+
+	links := RelativeLinks(content)
+	require.Len(t, links, 2)
+	assert.Equal(t, "docs/a.md", links[0].Dest)
+	assert.Equal(t, 1, links[0].Line)
+	assert.Equal(t, "../b.md#top", links[1].Dest)
+	assert.True(t, links[1].Angled)
+	assert.Equal(t, 6, links[1].Line)
+}
+
+func TestSplitLinkDest(t *testing.T) {
+	p, suffix := SplitLinkDest("a/b.md#sec?x=1")
+	assert.Equal(t, "a/b.md", p)
+	assert.Equal(t, "#sec?x=1", suffix)
+}
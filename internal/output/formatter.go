@@ -8,6 +8,8 @@ type ValidationError struct {
 	Message     string
 	Remediation string
 	Severity    string // "error" or "warning"
+	File        string // file the entry refers to, when not the validated file
+	Line        int    // 1-based line in File; 0 when not applicable
 }
 
 // ValidationResult represents the outcome of config validation for formatting.
@@ -16,6 +18,9 @@ type ValidationResult struct {
 	Errors   []ValidationError
 	Warnings []ValidationError
 	File     string
+	// Rules lists the rules that were checked, as RuleID reports them.
+	// Formats that report passing checks, like JUnit, list each of them.
+	Rules []string
 }
 
 // Formatter defines the interface for formatting validation output.
@@ -25,6 +30,13 @@ type Formatter interface {
 	FormatWarnings(result ValidationResult) string
 }
 
+// FailureFormatter defines the interface for formats whose consumers
+// expect a report even when a command fails before producing findings,
+// such as a CI upload step. message describes the failure.
+type FailureFormatter interface {
+	FormatFailure(message string) string
+}
+
 // SyncFormatter defines the interface for formatting sync results.
 type SyncFormatter interface {
 	FormatSyncResult(result SyncResult) string
@@ -34,16 +46,99 @@ type SyncFormatter interface {
 type SyncResult struct {
 	DryRun       bool
 	HubPath      string
-	HubStatus    string // "written", "unchanged"
+	HubStatus    string // "written", "unchanged", "rolled_back"
 	Links        []LinkResult
 	Warnings     []string
 	OverlayCount int
+	// RolledBack is set when an atomic sync failed and restored every
+	// file it had changed.
+	RolledBack     bool
+	RollbackErrors []string
+	// GitFiles lists .gitignore and .gitattributes files whose ailign
+	// block changed or failed to.
+	GitFiles []GitFileResult
+	// Commit is set when sync was asked to commit the files it changed.
+	Commit *CommitResult
+}
+
+// CommitResult represents the commit "ailign sync --commit" created for
+// formatting. An empty SHA means there was nothing to commit.
+type CommitResult struct {
+	SHA     string
+	Subject string
+	Files   []string
+}
+
+// GitFileResult represents a change to the ailign block of .gitignore
+// or .gitattributes for formatting.
+type GitFileResult struct {
+	Path   string
+	Status string // "written", "removed", "error"
+	Error  string
 }
 
-// LinkResult represents a per-target symlink outcome for formatting.
+// LinkResult represents a per-target symlink or copy outcome for formatting.
 type LinkResult struct {
 	Target   string
 	LinkPath string
-	Status   string // "created", "exists", "replaced", "error"
+	Mode     string // "symlink" or "copy"
+	Status   string // "created", "exists", "replaced", "skipped", "removed", "rolled_back", "error"
 	Error    string
 }
+
+// StatsFormatter defines the interface for formatting size statistics.
+type StatsFormatter interface {
+	FormatStats(result StatsResult) string
+}
+
+// StatsResult represents size statistics for the hub and each target.
+type StatsResult struct {
+	Hub     StatsFile
+	Targets []StatsFile
+}
+
+// StatsFile represents the size of one output file and its breakdown by
+// source overlay.
+type StatsFile struct {
+	Target string // empty for the hub
+	Path   string
+	StatsFigures
+	Limit int // character limit; 0 when unknown
+	Parts []StatsPart
+}
+
+// StatsPart represents one source's share of a file. The managed header
+// is reported as a part with Source "(header)".
+type StatsPart struct {
+	Source string
+	StatsFigures
+}
+
+// StatsFigures holds size measurements; Tokens is approximate.
+type StatsFigures struct {
+	Bytes  int
+	Chars  int
+	Lines  int
+	Tokens int
+}
+
+// CheckFormatter defines the interface for formatting check results.
+type CheckFormatter interface {
+	FormatCheck(result CheckResult) string
+}
+
+// CheckResult represents whether the outputs on disk match what sync
+// would write.
+type CheckResult struct {
+	InSync bool
+	Drift  []DriftEntry
+	Errors []string // targets that could not be checked
+}
+
+// DriftEntry describes one output sync would change, or one that was
+// edited outside ailign.
+type DriftEntry struct {
+	Path   string
+	Change string // "written", "created", "replaced", "removed" or "edited"
+	Detail string // for "edited", where the change belongs instead
+}
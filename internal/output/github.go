@@ -0,0 +1,122 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GitHubFormatter formats results as GitHub Actions workflow commands,
+// so that errors and warnings show up as annotations on the lines they
+// refer to. Everything else is printed as the human format does.
+type GitHubFormatter struct {
+	human HumanFormatter
+}
+
+// annotation returns a workflow command such as
+// "::error file=.ailign.yml,line=3,title=config.targets::message".
+// An empty file annotates the run rather than a file.
+func annotation(level, file string, line int, title, message string) string {
+	var props []string
+	if file != "" {
+		props = append(props, "file="+escapeProperty(file))
+		if line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", line))
+		}
+	}
+	if title != "" {
+		props = append(props, "title="+escapeProperty(title))
+	}
+	cmd := "::" + level
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	return cmd + "::" + escapeData(message) + "\n"
+}
+
+// escapeData escapes a workflow command's message.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// entryAnnotation annotates a validation entry. Entries without a file
+// of their own concern the configuration.
+func entryAnnotation(level string, e ValidationError) string {
+	file := e.File
+	if file == "" {
+		file = sarifConfigFile
+	}
+	return annotation(level, file, e.Line, RuleID(e), sarifText(e))
+}
+
+// FormatSuccess returns the human success line; warnings are annotated
+// by FormatWarnings.
+func (f *GitHubFormatter) FormatSuccess(result ValidationResult) string {
+	return f.human.FormatSuccess(result)
+}
+
+// FormatErrors returns an error annotation per error and a summary.
+func (f *GitHubFormatter) FormatErrors(result ValidationResult) string {
+	var b strings.Builder
+	for _, e := range result.Errors {
+		b.WriteString(entryAnnotation("error", e))
+	}
+	n := len(result.Errors)
+	fmt.Fprintf(&b, "%s validation failed: %d %s found\n", result.File, n, pluralize("error", n))
+	return b.String()
+}
+
+// FormatWarnings returns a warning annotation per warning.
+func (f *GitHubFormatter) FormatWarnings(result ValidationResult) string {
+	var b strings.Builder
+	for _, w := range result.Warnings {
+		b.WriteString(entryAnnotation("warning", w))
+	}
+	return b.String()
+}
+
+// FormatSyncResult returns the human sync report followed by an
+// annotation for each failure and warning.
+func (f *GitHubFormatter) FormatSyncResult(result SyncResult) string {
+	var b strings.Builder
+	b.WriteString(f.human.FormatSyncResult(result))
+	for _, l := range result.Links {
+		if l.Status == "error" {
+			b.WriteString(annotation("error", l.LinkPath, 0, "sync.target", fmt.Sprintf("%s: %s", l.Target, l.Error)))
+		}
+	}
+	for _, g := range result.GitFiles {
+		if g.Status == "error" {
+			b.WriteString(annotation("error", g.Path, 0, "sync.git_file", g.Error))
+		}
+	}
+	for _, e := range result.RollbackErrors {
+		b.WriteString(annotation("error", "", 0, "sync.rollback", e))
+	}
+	for _, w := range result.Warnings {
+		b.WriteString(annotation("warning", "", 0, "sync.warning", w))
+	}
+	return b.String()
+}
+
+// FormatCheck returns the human check report followed by an error
+// annotation on each file that is out of date or could not be checked.
+func (f *GitHubFormatter) FormatCheck(result CheckResult) string {
+	var b strings.Builder
+	b.WriteString(f.human.FormatCheck(result))
+	for _, d := range result.Drift {
+		message := fmt.Sprintf("%s would be %s; run \"ailign sync\" to update it", d.Path, d.Change)
+		if d.Change == "edited" {
+			message = fmt.Sprintf("%s was edited outside ailign; %s", d.Path, d.Detail)
+		}
+		b.WriteString(annotation("error", d.Path, 0, "check.drift", message))
+	}
+	for _, e := range result.Errors {
+		b.WriteString(annotation("error", "", 0, "check.error", e))
+	}
+	return b.String()
+}
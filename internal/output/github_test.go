@@ -0,0 +1,66 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubFormatErrors(t *testing.T) {
+	f := &GitHubFormatter{}
+	result := ValidationResult{
+		File: ".ailign.yml",
+		Errors: []ValidationError{
+			{FieldPath: "targets[1]", Message: "unknown target", Actual: "notepad", Remediation: "Use one of: claude, cursor", Line: 3, Severity: "error"},
+			{FieldPath: "lint.broken_links", Message: "link target 100% missing", File: "docs/a,b.md", Severity: "error"},
+		},
+	}
+
+	assert.Equal(t,
+		"::error file=.ailign.yml,line=3,title=config.targets::unknown target%0AFound: notepad%0AFix: Use one of: claude, cursor\n"+
+			"::error file=docs/a%2Cb.md,title=lint.broken_links::link target 100%25 missing\n"+
+			".ailign.yml validation failed: 2 errors found\n",
+		f.FormatErrors(result))
+}
+
+func TestGitHubFormatWarnings(t *testing.T) {
+	f := &GitHubFormatter{}
+	result := ValidationResult{
+		Valid: true,
+		File:  "overlays",
+		Warnings: []ValidationError{
+			{FieldPath: "lint.long_lines", Message: "line is too long", File: "base.md", Line: 7, Severity: "warning"},
+		},
+	}
+
+	assert.Equal(t, "::warning file=base.md,line=7,title=lint.long_lines::line is too long\n", f.FormatWarnings(result))
+	assert.Equal(t, "overlays: valid (1 warning)\n", f.FormatSuccess(result))
+}
+
+func TestGitHubFormatSyncResult(t *testing.T) {
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "written",
+		Links: []LinkResult{
+			{Target: "cursor", LinkPath: ".cursorrules", Mode: "symlink", Status: "error", Error: "permission denied"},
+		},
+		Warnings: []string{"overlay base.md is empty"},
+	}
+
+	got := (&GitHubFormatter{}).FormatSyncResult(result)
+	assert.Contains(t, got, "Syncing instructions to 1 target...")
+	assert.Contains(t, got, "::error file=.cursorrules,title=sync.target::cursor: permission denied\n")
+	assert.Contains(t, got, "::warning title=sync.warning::overlay base.md is empty\n")
+}
+
+func TestGitHubFormatCheck(t *testing.T) {
+	result := CheckResult{Drift: []DriftEntry{
+		{Path: ".cursorrules", Change: "replaced"},
+		{Path: ".ailign/instructions.md", Change: "edited", Detail: "move the change into base.md"},
+	}}
+
+	got := (&GitHubFormatter{}).FormatCheck(result)
+	assert.Contains(t, got, "2 instruction files out of date with the overlays:\n")
+	assert.Contains(t, got, "::error file=.cursorrules,title=check.drift::.cursorrules would be replaced; run \"ailign sync\" to update it\n")
+	assert.Contains(t, got, "::error file=.ailign/instructions.md,title=check.drift::.ailign/instructions.md was edited outside ailign; move the change into base.md\n")
+}
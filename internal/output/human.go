@@ -47,8 +47,14 @@ func (f *HumanFormatter) FormatWarnings(result ValidationResult) string {
 }
 
 // formatEntry writes a single error or warning entry to the builder.
+// Entries that point into a file are prefixed with file:line, followed
+// by the field path of the rule that produced them.
 func formatEntry(b *strings.Builder, e ValidationError) {
-	fmt.Fprintf(b, "  %s: %s\n", e.FieldPath, e.Message)
+	if e.File != "" {
+		fmt.Fprintf(b, "  %s: %s (%s)\n", location(e), e.Message, e.FieldPath)
+	} else {
+		fmt.Fprintf(b, "  %s: %s\n", e.FieldPath, e.Message)
+	}
 	if e.Expected != "" {
 		fmt.Fprintf(b, "    Expected: %s\n", e.Expected)
 	}
@@ -64,7 +70,16 @@ func formatEntry(b *strings.Builder, e ValidationError) {
 func (f *HumanFormatter) FormatSyncResult(result SyncResult) string {
 	var b strings.Builder
 
-	totalTargets := len(result.Links)
+	var skipped, removed []string
+	for _, link := range result.Links {
+		switch link.Status {
+		case "skipped":
+			skipped = append(skipped, link.Target)
+		case "removed":
+			removed = append(removed, link.Target)
+		}
+	}
+	totalTargets := len(result.Links) - len(skipped) - len(removed)
 
 	if result.DryRun {
 		b.WriteString("Dry run — no files will be modified.\n")
@@ -78,6 +93,8 @@ func (f *HumanFormatter) FormatSyncResult(result SyncResult) string {
 	hubLabel := result.HubPath
 	if result.DryRun {
 		fmt.Fprintf(&b, "  %-40s %s\n", hubLabel, dryRunHubStatus(result.HubStatus))
+	} else if result.HubStatus == "rolled_back" {
+		fmt.Fprintf(&b, "  %-40s rolled back\n", hubLabel)
 	} else {
 		fmt.Fprintf(&b, "  %-40s %s\n", hubLabel, result.HubStatus)
 	}
@@ -87,10 +104,23 @@ func (f *HumanFormatter) FormatSyncResult(result SyncResult) string {
 		label := link.LinkPath
 		if link.Status == "error" {
 			fmt.Fprintf(&b, "  %-40s error: %s\n", label, link.Error)
+		} else if link.Status == "skipped" {
+			fmt.Fprintf(&b, "  %-40s skipped\n", label)
+		} else if link.Status == "rolled_back" {
+			fmt.Fprintf(&b, "  %-40s rolled back\n", label)
 		} else if result.DryRun {
-			fmt.Fprintf(&b, "  %-40s %s\n", label, dryRunLinkStatus(link.Status))
+			fmt.Fprintf(&b, "  %-40s %s\n", label, dryRunLinkStatus(link.Mode, link.Status))
 		} else {
-			fmt.Fprintf(&b, "  %-40s %s\n", label, humanLinkStatus(link.Status))
+			fmt.Fprintf(&b, "  %-40s %s\n", label, humanLinkStatus(link.Mode, link.Status))
+		}
+	}
+
+	// ailign blocks in .gitignore and .gitattributes
+	for _, g := range result.GitFiles {
+		if g.Status == "error" {
+			fmt.Fprintf(&b, "  %-40s error: %s\n", g.Path, g.Error)
+		} else {
+			fmt.Fprintf(&b, "  %-40s %s\n", g.Path, gitFileStatus(result.DryRun, g.Status))
 		}
 	}
 
@@ -110,7 +140,12 @@ func (f *HumanFormatter) FormatSyncResult(result SyncResult) string {
 	}
 
 	overlayCount := result.OverlayCount
-	if errors > 0 {
+	if result.RolledBack {
+		fmt.Fprintf(&b, "Sync failed (%d %s); rolled back all changes.\n", errors, pluralize("error", errors))
+		for _, e := range result.RollbackErrors {
+			fmt.Fprintf(&b, "  rollback error: %s\n", e)
+		}
+	} else if errors > 0 {
 		fmt.Fprintf(&b, "Synced %d of %d %s from %d %s (%d %s).\n",
 			totalTargets-errors, totalTargets, pluralize("target", totalTargets),
 			overlayCount, pluralize("overlay", overlayCount),
@@ -127,9 +162,50 @@ func (f *HumanFormatter) FormatSyncResult(result SyncResult) string {
 			overlayCount, pluralize("overlay", overlayCount))
 	}
 
+	if len(skipped) > 0 {
+		fmt.Fprintf(&b, "Skipped %d %s: %s.\n", len(skipped), pluralize("target", len(skipped)), strings.Join(skipped, ", "))
+	}
+	if len(removed) > 0 {
+		verb := "Removed"
+		if result.DryRun {
+			verb = "Would remove"
+		}
+		fmt.Fprintf(&b, "%s %d orphaned %s: %s.\n", verb, len(removed), pluralize("target", len(removed)), strings.Join(removed, ", "))
+	}
+	if c := result.Commit; c != nil {
+		if c.SHA == "" {
+			b.WriteString("Nothing to commit.\n")
+		} else {
+			fmt.Fprintf(&b, "Committed %d %s as %s: %s\n", len(c.Files), pluralize("file", len(c.Files)), shortSHA(c.SHA), c.Subject)
+		}
+	}
+
 	return b.String()
 }
 
+// shortSHA abbreviates a commit hash the way git log --oneline does.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func gitFileStatus(dryRun bool, status string) string {
+	switch {
+	case dryRun && status == "removed":
+		return "would remove ailign block"
+	case dryRun:
+		return "would update ailign block"
+	case status == "rolled_back":
+		return "rolled back"
+	case status == "removed":
+		return "ailign block removed"
+	default:
+		return "ailign block updated"
+	}
+}
+
 func dryRunHubStatus(status string) string {
 	switch status {
 	case "unchanged":
@@ -139,30 +215,126 @@ func dryRunHubStatus(status string) string {
 	}
 }
 
-func dryRunLinkStatus(status string) string {
+func dryRunLinkStatus(mode, status string) string {
+	noun := linkNoun(mode)
 	switch status {
 	case "exists":
-		return "symlink ok"
+		return noun + " ok"
 	case "replaced":
-		return "would replace symlink"
+		return "would replace " + noun
+	case "removed":
+		return "would remove " + noun
 	default:
-		return "would create symlink"
+		return "would create " + noun
 	}
 }
 
-func humanLinkStatus(status string) string {
+func humanLinkStatus(mode, status string) string {
+	noun := linkNoun(mode)
 	switch status {
 	case "created":
-		return "symlink created"
+		return noun + " created"
 	case "exists":
-		return "symlink ok"
+		return noun + " ok"
 	case "replaced":
-		return "symlink replaced"
+		return noun + " replaced"
+	case "removed":
+		return noun + " removed"
 	default:
 		return status
 	}
 }
 
+// linkNoun names what a target entry is in the given mode.
+func linkNoun(mode string) string {
+	if mode == "copy" {
+		return "file"
+	}
+	return "symlink"
+}
+
+// FormatStats formats size statistics as one table per file, with a row
+// per source and each figure's share of the target's limit.
+func (f *HumanFormatter) FormatStats(result StatsResult) string {
+	var b strings.Builder
+	writeStatsFile(&b, "Hub "+result.Hub.Path, result.Hub)
+	for _, t := range result.Targets {
+		b.WriteString("\n")
+		writeStatsFile(&b, t.Target+" ("+t.Path+")", t)
+	}
+	return b.String()
+}
+
+func writeStatsFile(b *strings.Builder, title string, file StatsFile) {
+	switch {
+	case file.Limit > 0:
+		fmt.Fprintf(b, "%s — limit %d characters\n", title, file.Limit)
+	case file.Target != "":
+		fmt.Fprintf(b, "%s — no documented limit (set lint.size_limits.%s to check one)\n", title, file.Target)
+	default:
+		fmt.Fprintf(b, "%s\n", title)
+	}
+
+	width := len("total")
+	for _, p := range file.Parts {
+		if len(p.Source) > width {
+			width = len(p.Source)
+		}
+	}
+	writeStatsRow(b, width, "total", file.StatsFigures, file.Limit)
+	for _, p := range file.Parts {
+		writeStatsRow(b, width, p.Source, p.StatsFigures, file.Limit)
+	}
+	if file.Limit > 0 && file.Chars > file.Limit {
+		fmt.Fprintf(b, "  Over the limit by %d characters.\n", file.Chars-file.Limit)
+	}
+}
+
+func writeStatsRow(b *strings.Builder, width int, label string, fig StatsFigures, limit int) {
+	fmt.Fprintf(b, "  %-*s  %7d bytes  %5d lines  %7s tokens", width, label, fig.Bytes, fig.Lines, fmt.Sprintf("~%d", fig.Tokens))
+	if limit > 0 {
+		fmt.Fprintf(b, "  %3d%% of limit", fig.Chars*100/limit)
+	}
+	b.WriteString("\n")
+}
+
+// FormatCheck formats a check result as a list of the outputs sync
+// would change, or a one-line confirmation that there are none.
+func (f *HumanFormatter) FormatCheck(result CheckResult) string {
+	var b strings.Builder
+	if len(result.Errors) > 0 {
+		n := len(result.Errors)
+		fmt.Fprintf(&b, "Could not check %d %s:\n", n, pluralize("target", n))
+		for _, e := range result.Errors {
+			fmt.Fprintf(&b, "  %s\n", e)
+		}
+	}
+	if len(result.Drift) > 0 {
+		n := len(result.Drift)
+		fmt.Fprintf(&b, "%d instruction %s out of date with the overlays:\n", n, pluralize("file", n))
+		for _, d := range result.Drift {
+			if d.Change == "edited" {
+				fmt.Fprintf(&b, "  %-40s edited outside ailign; %s\n", d.Path, d.Detail)
+			} else {
+				fmt.Fprintf(&b, "  %-40s would be %s\n", d.Path, d.Change)
+			}
+		}
+		b.WriteString("Run \"ailign sync\" to update them.\n")
+	}
+	if result.InSync {
+		b.WriteString("All instruction files are in sync.\n")
+	}
+	return b.String()
+}
+
+// location formats an entry's file position as "file" or "file:line".
+func location(e ValidationError) string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+	return e.File
+}
+
 // pluralize returns the singular or plural form of a word based on count.
 func pluralize(word string, count int) string {
 	if count == 1 {
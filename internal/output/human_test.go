@@ -55,6 +55,30 @@ func TestHumanFormatSyncResult_AllUpToDate(t *testing.T) {
 	assert.Contains(t, got, "All 2 targets up to date")
 }
 
+func TestHumanFormatSyncResult_CopyMode(t *testing.T) {
+	f := &HumanFormatter{}
+	result := SyncResult{
+		HubPath:      ".ailign/instructions.md",
+		HubStatus:    "written",
+		OverlayCount: 1,
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Mode: "copy", Status: "created"},
+			{Target: "cursor", LinkPath: ".cursorrules", Mode: "copy", Status: "replaced"},
+		},
+	}
+
+	got := f.FormatSyncResult(result)
+
+	assert.Contains(t, got, "file created")
+	assert.Contains(t, got, "file replaced")
+	assert.NotContains(t, got, "symlink")
+
+	result.DryRun = true
+	got = f.FormatSyncResult(result)
+	assert.Contains(t, got, "would create file")
+	assert.Contains(t, got, "would replace file")
+}
+
 func TestHumanFormatSyncResult_WithErrors(t *testing.T) {
 	f := &HumanFormatter{}
 	result := SyncResult{
@@ -426,3 +450,190 @@ func TestHumanFormatWarnings_WithExpectedButNoActual(t *testing.T) {
 `
 	assert.Equal(t, expected, got)
 }
+
+func TestHumanFormatWarnings_WithFileLocation(t *testing.T) {
+	f := &HumanFormatter{}
+	result := ValidationResult{
+		Valid: true,
+		File:  "overlays",
+		Warnings: []ValidationError{
+			{
+				FieldPath:   "lint.trailing_whitespace",
+				Message:     "line has trailing whitespace",
+				Remediation: "Remove the trailing spaces or tabs",
+				File:        "base.md",
+				Line:        3,
+			},
+		},
+	}
+
+	got := f.FormatWarnings(result)
+
+	assert.Contains(t, got, "  base.md:3: line has trailing whitespace (lint.trailing_whitespace)\n")
+}
+
+func TestHumanFormatStats(t *testing.T) {
+	f := &HumanFormatter{}
+	result := StatsResult{
+		Hub: StatsFile{
+			Path:         ".ailign/instructions.md",
+			StatsFigures: StatsFigures{Bytes: 120, Chars: 120, Lines: 8, Tokens: 30},
+			Parts: []StatsPart{
+				{Source: "(header)", StatsFigures: StatsFigures{Bytes: 100, Chars: 100, Lines: 5, Tokens: 25}},
+				{Source: "base.md", StatsFigures: StatsFigures{Bytes: 20, Chars: 20, Lines: 3, Tokens: 5}},
+			},
+		},
+		Targets: []StatsFile{
+			{
+				Target:       "windsurf",
+				Path:         ".windsurfrules",
+				StatsFigures: StatsFigures{Bytes: 7000, Chars: 7000, Lines: 200, Tokens: 1750},
+				Limit:        6000,
+				Parts: []StatsPart{
+					{Source: "base.md", StatsFigures: StatsFigures{Bytes: 7000, Chars: 7000, Lines: 200, Tokens: 1750}},
+				},
+			},
+		},
+	}
+
+	got := f.FormatStats(result)
+
+	expected := `Hub .ailign/instructions.md
+  total         120 bytes      8 lines      ~30 tokens
+  (header)      100 bytes      5 lines      ~25 tokens
+  base.md        20 bytes      3 lines       ~5 tokens
+
+windsurf (.windsurfrules) — limit 6000 characters
+  total       7000 bytes    200 lines    ~1750 tokens  116% of limit
+  base.md     7000 bytes    200 lines    ~1750 tokens  116% of limit
+  Over the limit by 1000 characters.
+`
+	assert.Equal(t, expected, got)
+}
+
+func TestHumanFormatSyncResult_Skipped(t *testing.T) {
+	f := &HumanFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "written",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "created"},
+			{Target: "copilot", LinkPath: ".github/copilot-instructions.md", Status: "skipped"},
+		},
+		OverlayCount: 1,
+	}
+
+	got := f.FormatSyncResult(result)
+
+	assert.Contains(t, got, "Syncing instructions to 1 target...")
+	assert.Contains(t, got, ".github/copilot-instructions.md")
+	assert.Contains(t, got, "skipped\n")
+	assert.Contains(t, got, "Synced 1 target from 1 overlay.\nSkipped 1 target: copilot.\n")
+}
+
+func TestHumanFormatSyncResult_RolledBack(t *testing.T) {
+	f := &HumanFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "rolled_back",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "rolled_back"},
+			{Target: "cursor", LinkPath: ".cursorrules", Status: "error", Error: "permission denied"},
+		},
+		GitFiles:       []GitFileResult{{Path: ".gitignore", Status: "rolled_back"}},
+		OverlayCount:   1,
+		RolledBack:     true,
+		RollbackErrors: []string{"restoring .claude/instructions.md: busy"},
+	}
+
+	got := f.FormatSyncResult(result)
+
+	assert.Contains(t, got, "rolled back\n")
+	assert.Regexp(t, `\.gitignore +rolled back\n`, got)
+	assert.Contains(t, got, "permission denied")
+	assert.Contains(t, got, "Sync failed (1 error); rolled back all changes.\n")
+	assert.Contains(t, got, "  rollback error: restoring .claude/instructions.md: busy\n")
+}
+
+func TestHumanFormatSyncResult_Removed(t *testing.T) {
+	f := &HumanFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "unchanged",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Mode: "symlink", Status: "exists"},
+			{Target: "cursor", LinkPath: ".cursorrules", Mode: "symlink", Status: "removed"},
+		},
+		OverlayCount: 1,
+	}
+
+	got := f.FormatSyncResult(result)
+
+	assert.Contains(t, got, "Syncing instructions to 1 target...")
+	assert.Contains(t, got, "symlink removed\n")
+	assert.Contains(t, got, "All 1 target up to date.\nRemoved 1 orphaned target: cursor.\n")
+
+	result.DryRun = true
+	got = f.FormatSyncResult(result)
+	assert.Contains(t, got, "would remove symlink\n")
+	assert.Contains(t, got, "Would remove 1 orphaned target: cursor.\n")
+}
+
+func TestHumanFormatCheck(t *testing.T) {
+	f := &HumanFormatter{}
+
+	assert.Equal(t, "All instruction files are in sync.\n", f.FormatCheck(CheckResult{InSync: true}))
+
+	got := f.FormatCheck(CheckResult{
+		Drift: []DriftEntry{
+			{Path: ".ailign/instructions.md", Change: "written"},
+			{Path: ".cursorrules", Change: "created"},
+		},
+		Errors: []string{"claude: permission denied"},
+	})
+	assert.Contains(t, got, "Could not check 1 target:\n  claude: permission denied\n")
+	assert.Contains(t, got, "2 instruction files out of date with the overlays:\n")
+	assert.Contains(t, got, ".cursorrules")
+	assert.Contains(t, got, "would be created\n")
+	assert.Contains(t, got, "Run \"ailign sync\" to update them.\n")
+	assert.NotContains(t, got, "in sync")
+}
+
+func TestHumanFormatSyncResult_GitFiles(t *testing.T) {
+	f := &HumanFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "written",
+		GitFiles: []GitFileResult{
+			{Path: ".gitignore", Status: "written"},
+			{Path: ".gitattributes", Status: "error", Error: "writing .gitattributes: permission denied"},
+		},
+	}
+
+	got := f.FormatSyncResult(result)
+	assert.Contains(t, got, "ailign block updated\n")
+	assert.Contains(t, got, "error: writing .gitattributes: permission denied\n")
+
+	result.DryRun = true
+	result.GitFiles = []GitFileResult{{Path: ".gitignore", Status: "removed"}}
+	assert.Contains(t, f.FormatSyncResult(result), "would remove ailign block\n")
+}
+
+func TestHumanFormatSyncResult_Commit(t *testing.T) {
+	f := &HumanFormatter{}
+	result := SyncResult{HubPath: ".ailign/instructions.md", HubStatus: "written"}
+	assert.NotContains(t, f.FormatSyncResult(result), "ommit")
+
+	result.Commit = &CommitResult{SHA: "0123456789abcdef", Subject: "Sync AI instructions", Files: []string{".ailign/instructions.md"}}
+	assert.Contains(t, f.FormatSyncResult(result), "Committed 1 file as 0123456: Sync AI instructions\n")
+
+	result.Commit = &CommitResult{}
+	assert.Contains(t, f.FormatSyncResult(result), "Nothing to commit.\n")
+}
+
+func TestHumanFormatCheck_Edited(t *testing.T) {
+	f := &HumanFormatter{}
+	got := f.FormatCheck(CheckResult{Drift: []DriftEntry{{Path: ".cursorrules", Change: "edited", Detail: "move the change into base.md"}}})
+	assert.Contains(t, got, "edited outside ailign; move the change into base.md\n")
+	assert.NotContains(t, got, "would be edited")
+}
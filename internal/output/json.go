@@ -13,6 +13,8 @@ type jsonValidationError struct {
 	Actual      *string `json:"actual"`
 	Message     string  `json:"message"`
 	Remediation string  `json:"remediation"`
+	File        string  `json:"file,omitempty"`
+	Line        int     `json:"line,omitempty"`
 }
 
 // jsonValidationResult is the JSON wire representation of a full validation result.
@@ -59,10 +61,27 @@ func marshalResult(result ValidationResult) string {
 
 // jsonSyncResult is the JSON wire representation of a sync result.
 type jsonSyncResult struct {
-	DryRun  bool            `json:"dry_run"`
-	Hub     jsonHub         `json:"hub"`
-	Links   []jsonLink      `json:"links"`
-	Summary jsonSyncSummary `json:"summary"`
+	DryRun         bool            `json:"dry_run"`
+	Hub            jsonHub         `json:"hub"`
+	Links          []jsonLink      `json:"links"`
+	Summary        jsonSyncSummary `json:"summary"`
+	RolledBack     bool            `json:"rolled_back"`
+	RollbackErrors []string        `json:"rollback_errors,omitempty"`
+	GitFiles       []jsonGitFile   `json:"git_files"`
+	Commit         *jsonCommit     `json:"commit,omitempty"`
+}
+
+type jsonCommit struct {
+	Committed bool     `json:"committed"`
+	SHA       string   `json:"sha,omitempty"`
+	Subject   string   `json:"subject,omitempty"`
+	Files     []string `json:"files"`
+}
+
+type jsonGitFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 type jsonHub struct {
@@ -73,6 +92,7 @@ type jsonHub struct {
 type jsonLink struct {
 	Target   string `json:"target"`
 	LinkPath string `json:"link_path"`
+	Mode     string `json:"mode,omitempty"`
 	Status   string `json:"status"`
 	Error    string `json:"error,omitempty"`
 }
@@ -81,12 +101,14 @@ type jsonSyncSummary struct {
 	Total    int `json:"total"`
 	Created  int `json:"created"`
 	Existing int `json:"existing"`
+	Skipped  int `json:"skipped"`
+	Removed  int `json:"removed"`
 	Errors   int `json:"errors"`
 }
 
 // FormatSyncResult returns the JSON representation of a sync result.
 func (f *JSONFormatter) FormatSyncResult(result SyncResult) string {
-	var created, existing, errCount int
+	var created, existing, skipped, removed, errCount int
 	links := make([]jsonLink, 0, len(result.Links))
 	for _, l := range result.Links {
 		links = append(links, jsonLink(l))
@@ -95,6 +117,10 @@ func (f *JSONFormatter) FormatSyncResult(result SyncResult) string {
 			created++
 		case "exists":
 			existing++
+		case "skipped":
+			skipped++
+		case "removed":
+			removed++
 		case "error":
 			errCount++
 		}
@@ -111,8 +137,22 @@ func (f *JSONFormatter) FormatSyncResult(result SyncResult) string {
 			Total:    len(result.Links),
 			Created:  created,
 			Existing: existing,
+			Skipped:  skipped,
+			Removed:  removed,
 			Errors:   errCount,
 		},
+		RolledBack:     result.RolledBack,
+		RollbackErrors: result.RollbackErrors,
+		GitFiles:       make([]jsonGitFile, 0, len(result.GitFiles)),
+	}
+	for _, g := range result.GitFiles {
+		jr.GitFiles = append(jr.GitFiles, jsonGitFile(g))
+	}
+	if c := result.Commit; c != nil {
+		jr.Commit = &jsonCommit{Committed: c.SHA != "", SHA: c.SHA, Subject: c.Subject, Files: c.Files}
+		if jr.Commit.Files == nil {
+			jr.Commit.Files = []string{}
+		}
 	}
 
 	data, err := json.MarshalIndent(jr, "", "  ")
@@ -122,6 +162,100 @@ func (f *JSONFormatter) FormatSyncResult(result SyncResult) string {
 	return string(data)
 }
 
+// jsonStatsResult is the JSON wire representation of size statistics.
+type jsonStatsResult struct {
+	Hub     jsonStatsFile   `json:"hub"`
+	Targets []jsonStatsFile `json:"targets"`
+}
+
+type jsonStatsFile struct {
+	Target string `json:"target,omitempty"`
+	Path   string `json:"path"`
+	jsonStatsFigures
+	Limit     *int            `json:"limit"` // null when unknown
+	OverLimit bool            `json:"over_limit"`
+	Sources   []jsonStatsPart `json:"sources"`
+}
+
+type jsonStatsPart struct {
+	Source string `json:"source"`
+	jsonStatsFigures
+}
+
+type jsonStatsFigures struct {
+	Bytes  int `json:"bytes"`
+	Chars  int `json:"chars"`
+	Lines  int `json:"lines"`
+	Tokens int `json:"tokens"`
+}
+
+// FormatStats returns the JSON representation of size statistics.
+func (f *JSONFormatter) FormatStats(result StatsResult) string {
+	jr := jsonStatsResult{
+		Hub:     convertStatsFile(result.Hub),
+		Targets: make([]jsonStatsFile, 0, len(result.Targets)),
+	}
+	for _, t := range result.Targets {
+		jr.Targets = append(jr.Targets, convertStatsFile(t))
+	}
+
+	data, err := json.MarshalIndent(jr, "", "  ")
+	if err != nil {
+		return `{"hub":{},"targets":[]}`
+	}
+	return string(data)
+}
+
+func convertStatsFile(file StatsFile) jsonStatsFile {
+	jf := jsonStatsFile{
+		Target:           file.Target,
+		Path:             file.Path,
+		jsonStatsFigures: jsonStatsFigures(file.StatsFigures),
+		OverLimit:        file.Limit > 0 && file.Chars > file.Limit,
+		Sources:          make([]jsonStatsPart, 0, len(file.Parts)),
+	}
+	if file.Limit > 0 {
+		limit := file.Limit
+		jf.Limit = &limit
+	}
+	for _, p := range file.Parts {
+		jf.Sources = append(jf.Sources, jsonStatsPart{Source: p.Source, jsonStatsFigures: jsonStatsFigures(p.StatsFigures)})
+	}
+	return jf
+}
+
+// jsonCheckResult is the JSON wire representation of a check result.
+type jsonCheckResult struct {
+	InSync bool        `json:"in_sync"`
+	Drift  []jsonDrift `json:"drift"`
+	Errors []string    `json:"errors"`
+}
+
+type jsonDrift struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// FormatCheck returns the JSON representation of a check result.
+func (f *JSONFormatter) FormatCheck(result CheckResult) string {
+	jr := jsonCheckResult{
+		InSync: result.InSync,
+		Drift:  make([]jsonDrift, 0, len(result.Drift)),
+		Errors: make([]string, 0, len(result.Errors)),
+	}
+	for _, d := range result.Drift {
+		jr.Drift = append(jr.Drift, jsonDrift(d))
+	}
+	jr.Errors = append(jr.Errors, result.Errors...)
+
+	data, err := json.MarshalIndent(jr, "", "  ")
+	if err != nil {
+		return `{"in_sync":false,"drift":[],"errors":[]}`
+	}
+	return string(data)
+}
+
 // convertErrors maps a slice of internal ValidationError values to the JSON wire
 // format. An empty or nil input slice produces a non-nil empty slice so that
 // json.Marshal emits [] rather than null.
@@ -133,6 +267,8 @@ func convertErrors(errs []ValidationError) []jsonValidationError {
 			Expected:    e.Expected,
 			Message:     e.Message,
 			Remediation: e.Remediation,
+			File:        e.File,
+			Line:        e.Line,
 		}
 		if e.Actual != "" {
 			a := e.Actual
@@ -324,8 +324,12 @@ type jsonSyncOutput struct {
 		Total    int `json:"total"`
 		Created  int `json:"created"`
 		Existing int `json:"existing"`
+		Skipped  int `json:"skipped"`
+		Removed  int `json:"removed"`
 		Errors   int `json:"errors"`
 	} `json:"summary"`
+	RolledBack     bool     `json:"rolled_back"`
+	RollbackErrors []string `json:"rollback_errors"`
 }
 
 func TestJSONFormatSyncResult_Success(t *testing.T) {
@@ -449,3 +453,175 @@ func TestJSON_SeverityOmitted(t *testing.T) {
 
 	assert.NotContains(t, out, "severity", "severity should not appear in JSON output")
 }
+
+func TestJSONFormatWarnings_FileLocation(t *testing.T) {
+	f := newJSONFormatter()
+	result := ValidationResult{
+		Valid: true,
+		Warnings: []ValidationError{
+			{FieldPath: "lint.long_lines", Message: "line is too long", File: "base.md", Line: 7, Severity: "warning"},
+			{FieldPath: "targets", Message: "no location", Severity: "warning"},
+		},
+		File: "overlays",
+	}
+
+	out := f.FormatWarnings(result)
+
+	var parsed struct {
+		Warnings []map[string]any `json:"warnings"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(out), &parsed))
+	assert.Equal(t, "base.md", parsed.Warnings[0]["file"])
+	assert.Equal(t, float64(7), parsed.Warnings[0]["line"])
+	assert.NotContains(t, parsed.Warnings[1], "file", "file is omitted when not set")
+	assert.NotContains(t, parsed.Warnings[1], "line", "line is omitted when not set")
+}
+
+func TestJSONFormatStats(t *testing.T) {
+	f := &JSONFormatter{}
+	result := StatsResult{
+		Hub: StatsFile{Path: ".ailign/instructions.md", StatsFigures: StatsFigures{Bytes: 10, Chars: 10, Lines: 1, Tokens: 3}},
+		Targets: []StatsFile{
+			{Target: "windsurf", Path: ".windsurfrules", StatsFigures: StatsFigures{Bytes: 7000, Chars: 7000}, Limit: 6000,
+				Parts: []StatsPart{{Source: "base.md", StatsFigures: StatsFigures{Bytes: 7000, Chars: 7000}}}},
+		},
+	}
+
+	var parsed map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(f.FormatStats(result)), &parsed))
+
+	hub := parsed["hub"].(map[string]any)
+	assert.Nil(t, hub["limit"], "unknown limit is null")
+	assert.NotContains(t, hub, "target")
+	assert.Equal(t, []any{}, hub["sources"], "sources is always an array")
+
+	ws := parsed["targets"].([]any)[0].(map[string]any)
+	assert.Equal(t, "windsurf", ws["target"])
+	assert.Equal(t, float64(6000), ws["limit"])
+	assert.Equal(t, true, ws["over_limit"])
+	source := ws["sources"].([]any)[0].(map[string]any)
+	assert.Equal(t, "base.md", source["source"])
+	assert.Equal(t, float64(7000), source["bytes"])
+}
+
+func TestJSONFormatSyncResult_Skipped(t *testing.T) {
+	f := &JSONFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "written",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "created"},
+			{Target: "copilot", LinkPath: ".github/copilot-instructions.md", Status: "skipped"},
+		},
+	}
+
+	var parsed jsonSyncOutput
+	assert.NoError(t, json.Unmarshal([]byte(f.FormatSyncResult(result)), &parsed))
+
+	assert.Equal(t, "skipped", parsed.Links[1].Status)
+	assert.Equal(t, 2, parsed.Summary.Total)
+	assert.Equal(t, 1, parsed.Summary.Created)
+	assert.Equal(t, 1, parsed.Summary.Skipped)
+}
+
+func TestJSONFormatSyncResult_RolledBack(t *testing.T) {
+	f := &JSONFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "rolled_back",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "rolled_back"},
+			{Target: "cursor", LinkPath: ".cursorrules", Status: "error", Error: "permission denied"},
+		},
+		RolledBack:     true,
+		RollbackErrors: []string{"restoring .claude/instructions.md: busy"},
+	}
+
+	var parsed jsonSyncOutput
+	assert.NoError(t, json.Unmarshal([]byte(f.FormatSyncResult(result)), &parsed))
+
+	assert.True(t, parsed.RolledBack)
+	assert.Equal(t, []string{"restoring .claude/instructions.md: busy"}, parsed.RollbackErrors)
+	assert.Equal(t, "rolled_back", parsed.Hub.Status)
+	assert.Equal(t, "rolled_back", parsed.Links[0].Status)
+	assert.Equal(t, 1, parsed.Summary.Errors)
+}
+
+func TestJSONFormatSyncResult_Removed(t *testing.T) {
+	f := &JSONFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "unchanged",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "exists"},
+			{Target: "cursor", LinkPath: ".cursorrules", Status: "removed"},
+		},
+	}
+
+	var parsed jsonSyncOutput
+	assert.NoError(t, json.Unmarshal([]byte(f.FormatSyncResult(result)), &parsed))
+
+	assert.Equal(t, "removed", parsed.Links[1].Status)
+	assert.Equal(t, 1, parsed.Summary.Existing)
+	assert.Equal(t, 1, parsed.Summary.Removed)
+}
+
+func TestJSONFormatCheck(t *testing.T) {
+	f := &JSONFormatter{}
+
+	var parsed struct {
+		InSync bool `json:"in_sync"`
+		Drift  []struct {
+			Path   string `json:"path"`
+			Change string `json:"change"`
+		} `json:"drift"`
+		Errors []string `json:"errors"`
+	}
+	got := f.FormatCheck(CheckResult{InSync: true})
+	assert.NoError(t, json.Unmarshal([]byte(got), &parsed))
+	assert.True(t, parsed.InSync)
+	assert.Contains(t, got, `"drift": []`)
+	assert.Contains(t, got, `"errors": []`)
+
+	got = f.FormatCheck(CheckResult{Drift: []DriftEntry{{Path: ".cursorrules", Change: "replaced"}}})
+	assert.NoError(t, json.Unmarshal([]byte(got), &parsed))
+	assert.False(t, parsed.InSync)
+	assert.Len(t, parsed.Drift, 1)
+	assert.Equal(t, ".cursorrules", parsed.Drift[0].Path)
+	assert.Equal(t, "replaced", parsed.Drift[0].Change)
+}
+
+func TestJSONFormatSyncResult_GitFiles(t *testing.T) {
+	f := &JSONFormatter{}
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "unchanged",
+		GitFiles:  []GitFileResult{{Path: ".gitignore", Status: "written"}},
+	}
+
+	got := f.FormatSyncResult(result)
+	assert.Contains(t, got, `"git_files": [`)
+	assert.Contains(t, got, `"path": ".gitignore"`)
+
+	assert.Contains(t, f.FormatSyncResult(SyncResult{}), `"git_files": []`)
+}
+
+func TestJSONFormatSyncResult_Commit(t *testing.T) {
+	f := &JSONFormatter{}
+	assert.NotContains(t, f.FormatSyncResult(SyncResult{}), `"commit"`)
+
+	got := f.FormatSyncResult(SyncResult{Commit: &CommitResult{SHA: "abc123", Subject: "Sync", Files: []string{".cursorrules"}}})
+	var parsed struct {
+		Commit struct {
+			Committed bool     `json:"committed"`
+			SHA       string   `json:"sha"`
+			Files     []string `json:"files"`
+		} `json:"commit"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(got), &parsed))
+	assert.True(t, parsed.Commit.Committed)
+	assert.Equal(t, "abc123", parsed.Commit.SHA)
+	assert.Equal(t, []string{".cursorrules"}, parsed.Commit.Files)
+
+	assert.Contains(t, f.FormatSyncResult(SyncResult{Commit: &CommitResult{}}), `"committed": false`)
+}
@@ -0,0 +1,217 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// JUnitFormatter formats results as JUnit XML, for CI systems that
+// render test reports. Each rule, or each target for sync, is a
+// testcase.
+type JUnitFormatter struct{}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"` // the run itself failed
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// marshalJUnit wraps suite in a testsuites document.
+func marshalJUnit(suite junitTestSuite) string {
+	for _, c := range suite.Cases {
+		switch {
+		case c.Failure != nil:
+			suite.Failures++
+		case c.Error != nil:
+			suite.Errors++
+		case c.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	suite.Tests = len(suite.Cases)
+	doc := junitTestSuites{
+		Name:     "ailign",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return xml.Header + `<testsuites name="ailign"></testsuites>` + "\n"
+	}
+	return xml.Header + string(data) + "\n"
+}
+
+// FormatFailure returns a JUnit report whose single testcase errored
+// with message, so CI shows the failed run instead of a missing report.
+func (f *JUnitFormatter) FormatFailure(message string) string {
+	return marshalJUnit(junitTestSuite{Name: "ailign", Cases: []junitTestCase{{
+		Name:      "run",
+		ClassName: "ailign",
+		Error:     &junitFailure{Message: message, Type: "error", Text: message},
+	}}})
+}
+
+// FormatSuccess returns the JUnit report for a successful validation.
+func (f *JUnitFormatter) FormatSuccess(result ValidationResult) string {
+	return junitValidation(result)
+}
+
+// FormatErrors returns the JUnit report for a failed validation.
+func (f *JUnitFormatter) FormatErrors(result ValidationResult) string {
+	return junitValidation(result)
+}
+
+// FormatWarnings returns the JUnit report with warnings embedded.
+func (f *JUnitFormatter) FormatWarnings(result ValidationResult) string {
+	return junitValidation(result)
+}
+
+// junitValidation reports one testcase per rule: every rule in
+// result.Rules, and any other rule with findings. A rule with errors
+// fails; warnings are listed in the testcase's output and don't fail it.
+// A result without rules or findings is a single passing testcase.
+func junitValidation(result ValidationResult) string {
+	rules := slices.Clone(result.Rules)
+	findings := map[string][]ValidationError{}
+	for _, e := range append(append([]ValidationError{}, result.Errors...), result.Warnings...) {
+		id := RuleID(e)
+		if !slices.Contains(rules, id) {
+			rules = append(rules, id)
+		}
+		findings[id] = append(findings[id], e)
+	}
+
+	suite := junitTestSuite{Name: result.File}
+	if len(rules) == 0 {
+		suite.Cases = append(suite.Cases, junitTestCase{Name: result.File, ClassName: result.File})
+	}
+	for _, id := range rules {
+		c := junitTestCase{Name: id, ClassName: result.File}
+		var failed []ValidationError
+		var failures, warnings []string
+		for _, e := range findings[id] {
+			if e.Severity == "error" {
+				failed = append(failed, e)
+				failures = append(failures, junitEntry(e))
+			} else {
+				warnings = append(warnings, junitEntry(e))
+			}
+		}
+		if len(failed) > 0 {
+			message := failed[0].Message
+			if len(failed) > 1 {
+				message = fmt.Sprintf("%s (and %d more)", message, len(failed)-1)
+			}
+			c.Failure = &junitFailure{Message: message, Type: id, Text: strings.Join(failures, "\n\n")}
+		}
+		c.SystemOut = strings.Join(warnings, "\n\n")
+		suite.Cases = append(suite.Cases, c)
+	}
+	return marshalJUnit(suite)
+}
+
+// junitEntry renders a finding as the human format does: location and
+// message, then the expected and actual values and the remediation.
+func junitEntry(e ValidationError) string {
+	var b strings.Builder
+	formatEntry(&b, e)
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "  ")
+	}
+	if e.Severity == "warning" {
+		lines[0] = "warning: " + lines[0]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatSyncResult returns a JUnit report with a testcase for the hub,
+// each target and each .gitignore or .gitattributes file sync updated.
+// Targets and files that failed are failures; skipped and rolled back
+// ones are skipped.
+func (f *JUnitFormatter) FormatSyncResult(result SyncResult) string {
+	suite := junitTestSuite{Name: "sync"}
+
+	hub := junitTestCase{Name: "hub", ClassName: "sync", SystemOut: fmt.Sprintf("%s: %s", result.HubPath, result.HubStatus)}
+	if result.HubStatus == "rolled_back" {
+		hub.Skipped = &junitSkipped{Message: "rolled back"}
+	}
+	suite.Cases = append(suite.Cases, hub)
+
+	for _, l := range result.Links {
+		c := junitTestCase{Name: l.Target, ClassName: "sync.targets", SystemOut: fmt.Sprintf("%s: %s", l.LinkPath, l.Status)}
+		switch l.Status {
+		case "error":
+			c.Failure = &junitFailure{
+				Message: l.Error,
+				Type:    "sync.target",
+				Text:    fmt.Sprintf("%s: %s\nFix: resolve the error, then run \"ailign sync\" again", l.Target, l.Error),
+			}
+		case "skipped":
+			c.Skipped = &junitSkipped{Message: "skipped"}
+		case "rolled_back":
+			c.Skipped = &junitSkipped{Message: "rolled back after another target failed"}
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	for _, g := range result.GitFiles {
+		c := junitTestCase{Name: g.Path, ClassName: "sync.git_files", SystemOut: fmt.Sprintf("%s: %s", g.Path, g.Status)}
+		switch g.Status {
+		case "error":
+			c.Failure = &junitFailure{Message: g.Error, Type: "sync.git_file", Text: g.Error}
+		case "rolled_back":
+			c.Skipped = &junitSkipped{Message: "rolled back after another change failed"}
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	if len(result.RollbackErrors) > 0 {
+		suite.Cases = append(suite.Cases, junitTestCase{Name: "rollback", ClassName: "sync", Failure: &junitFailure{
+			Message: fmt.Sprintf("%d %s could not be restored", len(result.RollbackErrors), pluralize("file", len(result.RollbackErrors))),
+			Type:    "sync.rollback",
+			Text:    strings.Join(result.RollbackErrors, "\n") + "\nFix: restore these files from version control",
+		}})
+	}
+	for _, w := range result.Warnings {
+		suite.Cases[0].SystemOut += "\nwarning: " + w
+	}
+	return marshalJUnit(suite)
+}
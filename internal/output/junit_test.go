@@ -0,0 +1,112 @@
+package output
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseJUnit(t *testing.T, out string) junitTestSuites {
+	t.Helper()
+	require.True(t, strings.HasPrefix(out, xml.Header), out)
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(out), &doc), out)
+	require.Len(t, doc.Suites, 1)
+	return doc
+}
+
+func TestJUnitFormatErrors(t *testing.T) {
+	f := &JUnitFormatter{}
+	result := ValidationResult{
+		File:  "policy",
+		Rules: []string{"policy.required_targets", "policy.forbidden_targets"},
+		Errors: []ValidationError{
+			{FieldPath: "policy.required_targets", Message: `target "claude" is required by policy`, Remediation: `Add "claude" under targets in .ailign.yml`, Severity: "error"},
+			{FieldPath: "policy.required_targets", Message: `target "copilot" is required by policy`, Severity: "error"},
+		},
+	}
+
+	doc := parseJUnit(t, f.FormatErrors(result))
+	assert.Equal(t, 2, doc.Tests)
+	assert.Equal(t, 1, doc.Failures)
+	suite := doc.Suites[0]
+	assert.Equal(t, "policy", suite.Name)
+	require.Len(t, suite.Cases, 2)
+
+	failed := suite.Cases[0]
+	assert.Equal(t, "policy.required_targets", failed.Name)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, `target "claude" is required by policy (and 1 more)`, failed.Failure.Message)
+	assert.Contains(t, failed.Failure.Text, `policy.required_targets: target "claude" is required by policy`)
+	assert.Contains(t, failed.Failure.Text, `Fix: Add "claude" under targets in .ailign.yml`)
+	assert.Contains(t, failed.Failure.Text, `target "copilot"`)
+
+	assert.Equal(t, "policy.forbidden_targets", suite.Cases[1].Name)
+	assert.Nil(t, suite.Cases[1].Failure)
+}
+
+func TestJUnitFormatFailure(t *testing.T) {
+	doc := parseJUnit(t, (&JUnitFormatter{}).FormatFailure("overlay file not found: base.md"))
+	assert.Equal(t, 1, doc.Tests)
+	assert.Equal(t, 0, doc.Failures)
+	assert.Equal(t, 1, doc.Errors)
+	suite := doc.Suites[0]
+	assert.Equal(t, 1, suite.Errors)
+	require.Len(t, suite.Cases, 1)
+	require.NotNil(t, suite.Cases[0].Error)
+	assert.Nil(t, suite.Cases[0].Failure)
+	assert.Equal(t, "overlay file not found: base.md", suite.Cases[0].Error.Message)
+}
+
+func TestJUnitFormatSuccess_WarningsDoNotFail(t *testing.T) {
+	f := &JUnitFormatter{}
+	result := ValidationResult{
+		Valid: true,
+		File:  "overlays",
+		Warnings: []ValidationError{
+			{FieldPath: "lint.long_lines", Message: "line is too long", File: "base.md", Line: 3, Severity: "warning"},
+		},
+	}
+
+	doc := parseJUnit(t, f.FormatSuccess(result))
+	assert.Equal(t, 0, doc.Failures)
+	require.Len(t, doc.Suites[0].Cases, 1)
+	c := doc.Suites[0].Cases[0]
+	assert.Equal(t, "lint.long_lines", c.Name)
+	assert.Nil(t, c.Failure)
+	assert.Equal(t, "warning: base.md:3: line is too long (lint.long_lines)", c.SystemOut)
+
+	doc = parseJUnit(t, f.FormatSuccess(ValidationResult{Valid: true, File: ".ailign.yml"}))
+	require.Len(t, doc.Suites[0].Cases, 1)
+	assert.Equal(t, ".ailign.yml", doc.Suites[0].Cases[0].Name)
+}
+
+func TestJUnitFormatSyncResult(t *testing.T) {
+	result := SyncResult{
+		HubPath:   ".ailign/instructions.md",
+		HubStatus: "written",
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "created"},
+			{Target: "cursor", LinkPath: ".cursorrules", Status: "error", Error: "permission denied"},
+			{Target: "windsurf", LinkPath: ".windsurfrules", Status: "skipped"},
+		},
+		Warnings: []string{"overlay base.md is empty"},
+	}
+
+	doc := parseJUnit(t, (&JUnitFormatter{}).FormatSyncResult(result))
+	assert.Equal(t, 4, doc.Tests)
+	assert.Equal(t, 1, doc.Failures)
+	assert.Equal(t, 1, doc.Skipped)
+
+	cases := doc.Suites[0].Cases
+	assert.Equal(t, "hub", cases[0].Name)
+	assert.Contains(t, cases[0].SystemOut, "warning: overlay base.md is empty")
+	assert.Nil(t, cases[1].Failure)
+	require.NotNil(t, cases[2].Failure)
+	assert.Equal(t, "permission denied", cases[2].Failure.Message)
+	assert.Contains(t, cases[2].Failure.Text, "Fix: ")
+	assert.NotNil(t, cases[3].Skipped)
+}
@@ -0,0 +1,278 @@
+package output
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SARIFFormatter formats results as SARIF 2.1.0, for GitHub code
+// scanning and other SARIF viewers.
+type SARIFFormatter struct{}
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifConfigFile is where findings without a file of their own are
+	// located: they concern the configuration.
+	sarifConfigFile = ".ailign.yml"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+// sarifInvocation records a run that failed before producing results.
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifBuilder collects results and the rules they refer to.
+type sarifBuilder struct {
+	rules       []sarifRule
+	index       map[string]int
+	results     []sarifResult
+	invocations []sarifInvocation
+}
+
+func (b *sarifBuilder) add(ruleID, description, severity, text, file string, line int, props map[string]string) {
+	i, ok := b.index[ruleID]
+	if !ok {
+		if b.index == nil {
+			b.index = map[string]int{}
+		}
+		i = len(b.rules)
+		b.index[ruleID] = i
+		b.rules = append(b.rules, sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: description}})
+	}
+
+	if file == "" {
+		file = sarifConfigFile
+	}
+	loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(file), URIBaseID: "%SRCROOT%"}}
+	if line > 0 {
+		loc.Region = &sarifRegion{StartLine: line}
+	}
+	b.results = append(b.results, sarifResult{
+		RuleID:     ruleID,
+		RuleIndex:  i,
+		Level:      sarifLevel(severity),
+		Message:    sarifMessage{Text: text},
+		Locations:  []sarifLocation{{PhysicalLocation: loc}},
+		Properties: props,
+	})
+}
+
+func (b *sarifBuilder) String() string {
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "ailign",
+				InformationURI: "https://github.com/ailign-cli/cli",
+				Rules:          b.rules,
+			}},
+			Invocations: b.invocations,
+			Results:     b.results,
+		}},
+	}
+	if log.Runs[0].Tool.Driver.Rules == nil {
+		log.Runs[0].Tool.Driver.Rules = []sarifRule{}
+	}
+	if log.Runs[0].Results == nil {
+		log.Runs[0].Results = []sarifResult{}
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return `{"version":"2.1.0","runs":[]}`
+	}
+	return string(data)
+}
+
+// FormatSuccess returns the SARIF log for a successful validation,
+// holding its warnings.
+func (f *SARIFFormatter) FormatSuccess(result ValidationResult) string {
+	return sarifValidation(result)
+}
+
+// FormatErrors returns the SARIF log for a failed validation.
+func (f *SARIFFormatter) FormatErrors(result ValidationResult) string {
+	return sarifValidation(result)
+}
+
+// FormatFailure returns a SARIF log without results whose invocation
+// failed with message, so code scanning records the failed run instead
+// of rejecting the upload.
+func (f *SARIFFormatter) FormatFailure(message string) string {
+	b := sarifBuilder{invocations: []sarifInvocation{{
+		ExecutionSuccessful: false,
+		ToolExecutionNotifications: []sarifNotification{{
+			Level:   "error",
+			Message: sarifMessage{Text: message},
+		}},
+	}}}
+	return b.String()
+}
+
+// FormatWarnings returns the SARIF log with warnings embedded.
+func (f *SARIFFormatter) FormatWarnings(result ValidationResult) string {
+	return sarifValidation(result)
+}
+
+func sarifValidation(result ValidationResult) string {
+	var b sarifBuilder
+	for _, e := range append(append([]ValidationError{}, result.Errors...), result.Warnings...) {
+		props := map[string]string{"fieldPath": e.FieldPath}
+		if e.Remediation != "" {
+			props["remediation"] = e.Remediation
+		}
+		b.add(RuleID(e), ruleDescription(e), e.Severity, sarifText(e), e.File, e.Line, props)
+	}
+	return b.String()
+}
+
+// sarifText renders an entry's message with its expected and actual
+// values and remediation, in the order the human format prints them.
+func sarifText(e ValidationError) string {
+	lines := []string{e.Message}
+	if e.Expected != "" {
+		lines = append(lines, "Expected: "+e.Expected)
+	}
+	if e.Actual != "" {
+		lines = append(lines, "Found: "+e.Actual)
+	}
+	if e.Remediation != "" {
+		lines = append(lines, "Fix: "+e.Remediation)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var fieldIndex = regexp.MustCompile(`\[\d+\]`)
+
+// RuleID returns the stable identifier of the rule that produced e, as
+// used by the machine-readable formats. Lint and policy findings keep
+// their rule's field path ("lint.long_lines", "policy.required_targets");
+// configuration findings are identified by their field with any list
+// indexes removed ("config.targets"). Unrecognized fields share
+// "config.unknown_field" whatever their name.
+func RuleID(e ValidationError) string {
+	switch {
+	case strings.HasPrefix(e.FieldPath, "lint."), strings.HasPrefix(e.FieldPath, "policy."):
+		return e.FieldPath
+	case e.Message == "unrecognized field":
+		return "config.unknown_field"
+	case e.FieldPath == sarifConfigFile:
+		return "config.file"
+	case e.FieldPath == "(internal)":
+		return "config.internal"
+	}
+	return "config." + fieldIndex.ReplaceAllString(e.FieldPath, "")
+}
+
+func ruleDescription(e ValidationError) string {
+	switch {
+	case strings.HasPrefix(e.FieldPath, "lint."):
+		return "Lint rule " + strings.TrimPrefix(e.FieldPath, "lint.")
+	case strings.HasPrefix(e.FieldPath, "policy."):
+		return "Policy rule " + strings.TrimPrefix(e.FieldPath, "policy.")
+	case e.Message == "unrecognized field":
+		return "Unrecognized field in .ailign.yml"
+	case e.FieldPath == sarifConfigFile || e.FieldPath == "(internal)":
+		return ".ailign.yml could not be loaded"
+	}
+	return "Invalid " + fieldIndex.ReplaceAllString(e.FieldPath, "") + " in .ailign.yml"
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case "error", "warning":
+		return severity
+	default:
+		return "note"
+	}
+}
+
+// FormatSyncResult returns a SARIF log holding the targets and files
+// sync failed to update, and its warnings.
+func (f *SARIFFormatter) FormatSyncResult(result SyncResult) string {
+	var b sarifBuilder
+	for _, l := range result.Links {
+		if l.Status == "error" {
+			b.add("sync.target", "A target could not be synced", "error", l.Error, l.LinkPath, 0, map[string]string{"target": l.Target})
+		}
+	}
+	for _, g := range result.GitFiles {
+		if g.Status == "error" {
+			b.add("sync.git_file", "The ailign block of a git file could not be updated", "error", g.Error, g.Path, 0, nil)
+		}
+	}
+	for _, e := range result.RollbackErrors {
+		b.add("sync.rollback", "A file could not be restored after a failed sync", "error", e, "", 0, nil)
+	}
+	for _, w := range result.Warnings {
+		b.add("sync.warning", "Sync warning", "warning", w, "", 0, nil)
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseSARIF(t *testing.T, out string) sarifLog {
+	t.Helper()
+	var log sarifLog
+	require.NoError(t, json.Unmarshal([]byte(out), &log), out)
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	return log
+}
+
+func TestSARIFFormatErrors(t *testing.T) {
+	f := &SARIFFormatter{}
+	result := ValidationResult{
+		File: "overlays",
+		Errors: []ValidationError{
+			{FieldPath: "targets[1]", Message: "unknown target", Expected: "one of claude, cursor", Actual: "notepad", Severity: "error"},
+		},
+		Warnings: []ValidationError{
+			{FieldPath: "lint.long_lines", Message: "line is too long", Remediation: "Wrap the line", File: "docs/base.md", Line: 7, Severity: "warning"},
+			{FieldPath: "lint.long_lines", Message: "line is too long", File: "team.md", Line: 2, Severity: "warning"},
+		},
+	}
+
+	log := parseSARIF(t, f.FormatErrors(result))
+	run := log.Runs[0]
+	assert.Equal(t, "ailign", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "config.targets", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "lint.long_lines", run.Tool.Driver.Rules[1].ID)
+
+	require.Len(t, run.Results, 3)
+	first := run.Results[0]
+	assert.Equal(t, "config.targets", first.RuleID)
+	assert.Equal(t, "error", first.Level)
+	assert.Equal(t, "unknown target\nExpected: one of claude, cursor\nFound: notepad", first.Message.Text)
+	assert.Equal(t, ".ailign.yml", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, first.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "targets[1]", first.Properties["fieldPath"])
+
+	lint := run.Results[1]
+	assert.Equal(t, 1, lint.RuleIndex)
+	assert.Equal(t, "warning", lint.Level)
+	assert.Equal(t, "docs/base.md", lint.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 7, lint.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "Wrap the line", lint.Properties["remediation"])
+	assert.Equal(t, 1, run.Results[2].RuleIndex)
+}
+
+func TestSARIFFormatSuccess_Empty(t *testing.T) {
+	out := (&SARIFFormatter{}).FormatSuccess(ValidationResult{Valid: true, File: ".ailign.yml"})
+	assert.Contains(t, out, `"results": []`)
+	assert.Contains(t, out, `"rules": []`)
+	parseSARIF(t, out)
+}
+
+func TestSARIFFormatFailure(t *testing.T) {
+	log := parseSARIF(t, (&SARIFFormatter{}).FormatFailure("overlay file not found: base.md"))
+	run := log.Runs[0]
+	assert.Empty(t, run.Results)
+	assert.Empty(t, run.Tool.Driver.Rules)
+	require.Len(t, run.Invocations, 1)
+	assert.False(t, run.Invocations[0].ExecutionSuccessful)
+	require.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+	notification := run.Invocations[0].ToolExecutionNotifications[0]
+	assert.Equal(t, "error", notification.Level)
+	assert.Equal(t, "overlay file not found: base.md", notification.Message.Text)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal([]byte((&SARIFFormatter{}).FormatSuccess(ValidationResult{Valid: true})), &raw))
+	assert.NotContains(t, raw["runs"].([]any)[0], "invocations", "successful runs carry no invocation")
+}
+
+func TestRuleID(t *testing.T) {
+	tests := []struct {
+		entry ValidationError
+		want  string
+	}{
+		{ValidationError{FieldPath: "lint.broken_links"}, "lint.broken_links"},
+		{ValidationError{FieldPath: "policy.required_targets"}, "policy.required_targets"},
+		{ValidationError{FieldPath: "targets[0].rules"}, "config.targets.rules"},
+		{ValidationError{FieldPath: "hub.header.text"}, "config.hub.header.text"},
+		{ValidationError{FieldPath: "custom_field", Message: "unrecognized field"}, "config.unknown_field"},
+		{ValidationError{FieldPath: ".ailign.yml", Message: "parsing config: bad"}, "config.file"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RuleID(tt.entry), tt.entry.FieldPath)
+	}
+}
+
+func TestSARIFFormatSyncResult(t *testing.T) {
+	result := SyncResult{
+		Links: []LinkResult{
+			{Target: "claude", LinkPath: ".claude/instructions.md", Status: "created"},
+			{Target: "cursor", LinkPath: ".cursorrules", Status: "error", Error: "permission denied"},
+		},
+		Warnings: []string{"overlay base.md is empty"},
+	}
+
+	log := parseSARIF(t, (&SARIFFormatter{}).FormatSyncResult(result))
+	results := log.Runs[0].Results
+	require.Len(t, results, 2)
+	assert.Equal(t, "sync.target", results[0].RuleID)
+	assert.Equal(t, ".cursorrules", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "sync.warning", results[1].RuleID)
+	assert.Equal(t, "warning", results[1].Level)
+}
@@ -0,0 +1,174 @@
+// Package policy checks a repository's ailign setup against a policy
+// file that an organization applies across all of its repositories.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/stats"
+	"github.com/ailign/cli/internal/target"
+	"github.com/goccy/go-yaml"
+)
+
+// Policy is what an organization requires of every repository's
+// ailign setup. Zero values impose nothing.
+type Policy struct {
+	// RequiredTargets must all be listed under targets.
+	RequiredTargets []string `yaml:"required_targets"`
+	// ForbiddenTargets must not be listed under targets.
+	ForbiddenTargets []string `yaml:"forbidden_targets"`
+	// RequiredOverlays must all be listed under local_overlays, as
+	// paths relative to the repository root.
+	RequiredOverlays []string `yaml:"required_overlays"`
+	// MaxInstructionChars and MaxInstructionTokens bound the size of the
+	// content each target receives. Tokens are approximate; see
+	// stats.Measure.
+	MaxInstructionChars  int `yaml:"max_instruction_chars"`
+	MaxInstructionTokens int `yaml:"max_instruction_tokens"`
+}
+
+// Load reads the policy file at path. Unknown keys are errors rather
+// than warnings: a misspelled rule would otherwise go unenforced.
+func Load(path string, registry *target.Registry) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("policy not found: %s", path)
+		}
+		return nil, fmt.Errorf("reading policy: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	var p Policy
+	if err := yaml.UnmarshalWithOptions(data, &p, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("parsing policy %s: %w", path, err)
+	}
+	if err := p.validate(registry); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// validate rejects policies no repository could satisfy or that name
+// targets ailign doesn't know.
+func (p *Policy) validate(registry *target.Registry) error {
+	for _, name := range append(slices.Clone(p.RequiredTargets), p.ForbiddenTargets...) {
+		if !registry.IsValid(name) {
+			return fmt.Errorf("unknown target %q; valid targets: %s", name, strings.Join(registry.KnownTargets(), ", "))
+		}
+	}
+	for _, name := range p.RequiredTargets {
+		if slices.Contains(p.ForbiddenTargets, name) {
+			return fmt.Errorf("target %q is both required and forbidden", name)
+		}
+	}
+	for _, o := range p.RequiredOverlays {
+		if o == "" || path.IsAbs(o) {
+			return fmt.Errorf("required overlay %q must be a path relative to the repository root", o)
+		}
+	}
+	if p.MaxInstructionChars < 0 || p.MaxInstructionTokens < 0 {
+		return fmt.Errorf("instruction size limits must not be negative")
+	}
+	return nil
+}
+
+// Rules lists the rules p sets, by their field path ("policy.<rule>").
+func (p *Policy) Rules() []string {
+	var rules []string
+	for _, r := range []struct {
+		name string
+		set  bool
+	}{
+		{"required_targets", len(p.RequiredTargets) > 0},
+		{"forbidden_targets", len(p.ForbiddenTargets) > 0},
+		{"required_overlays", len(p.RequiredOverlays) > 0},
+		{"max_instruction_chars", p.MaxInstructionChars > 0},
+		{"max_instruction_tokens", p.MaxInstructionTokens > 0},
+	} {
+		if r.set {
+			rules = append(rules, "policy."+r.name)
+		}
+	}
+	return rules
+}
+
+// Check evaluates cfg, and the content each configured target would
+// receive from sync, against p. Every violation is returned as an error
+// severity finding; an empty result means the repository complies.
+// policyFile names the policy in remediation hints. version is the
+// ailign version stamped into managed headers.
+func Check(baseDir string, cfg *config.Config, registry *target.Registry, p *Policy, policyFile, version string) ([]config.ValidationError, error) {
+	var violations []config.ValidationError
+	report := func(rule, file, message, expected, actual, remediation string) {
+		violations = append(violations, config.ValidationError{
+			FieldPath:   "policy." + rule,
+			Expected:    expected,
+			Actual:      actual,
+			Message:     message,
+			Remediation: remediation,
+			Severity:    config.SeverityError,
+			File:        file,
+		})
+	}
+
+	for _, name := range p.RequiredTargets {
+		if !slices.Contains(cfg.Targets, name) {
+			report("required_targets", "", fmt.Sprintf("target %q is required by policy", name),
+				fmt.Sprintf("targets to include %q", name), strings.Join(cfg.Targets, ", "),
+				fmt.Sprintf("Add %q under targets in .ailign.yml", name))
+		}
+	}
+	for _, name := range p.ForbiddenTargets {
+		if slices.Contains(cfg.Targets, name) {
+			report("forbidden_targets", "", fmt.Sprintf("target %q is forbidden by policy", name),
+				fmt.Sprintf("targets not to include %q", name), strings.Join(cfg.Targets, ", "),
+				fmt.Sprintf("Remove %q from targets in .ailign.yml", name))
+		}
+	}
+	for _, overlay := range p.RequiredOverlays {
+		if !slices.ContainsFunc(cfg.LocalOverlays, func(o string) bool { return sameOverlay(o, overlay) }) {
+			report("required_overlays", "", fmt.Sprintf("overlay %q is required by policy", overlay),
+				fmt.Sprintf("local_overlays to include %q", overlay), strings.Join(cfg.LocalOverlays, ", "),
+				fmt.Sprintf("Add %q under local_overlays in .ailign.yml", overlay))
+		}
+	}
+
+	if p.MaxInstructionChars == 0 && p.MaxInstructionTokens == 0 {
+		return violations, nil
+	}
+	sizes, err := stats.Compute(baseDir, cfg, registry, version)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range sizes.Targets {
+		if p.MaxInstructionChars > 0 && t.Chars > p.MaxInstructionChars {
+			report("max_instruction_chars", t.Path, fmt.Sprintf("%s instructions exceed the size allowed by policy", t.Target),
+				fmt.Sprintf("at most %d characters", p.MaxInstructionChars), fmt.Sprintf("%d characters", t.Chars),
+				sizeRemediation(policyFile))
+		}
+		if p.MaxInstructionTokens > 0 && t.Tokens > p.MaxInstructionTokens {
+			report("max_instruction_tokens", t.Path, fmt.Sprintf("%s instructions exceed the size allowed by policy", t.Target),
+				fmt.Sprintf("at most %d tokens", p.MaxInstructionTokens), fmt.Sprintf("about %d tokens", t.Tokens),
+				sizeRemediation(policyFile))
+		}
+	}
+	return violations, nil
+}
+
+// sameOverlay reports whether two overlay paths name the same file.
+func sameOverlay(a, b string) bool {
+	clean := func(p string) string { return path.Clean(strings.ReplaceAll(p, `\`, "/")) }
+	return clean(a) == clean(b)
+}
+
+func sizeRemediation(policyFile string) string {
+	return fmt.Sprintf("Shorten the overlays or move rarely needed guidance into linked documents; the limit is set in %s", policyFile)
+}
@@ -0,0 +1,107 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	writeFile(t, path, "required_targets: [claude]\nforbidden_targets: [windsurf]\nrequired_overlays: [docs/security.md]\nmax_instruction_chars: 100\n")
+
+	p, err := Load(path, target.NewDefaultRegistry())
+	require.NoError(t, err)
+	assert.Equal(t, &Policy{
+		RequiredTargets:     []string{"claude"},
+		ForbiddenTargets:    []string{"windsurf"},
+		RequiredOverlays:    []string{"docs/security.md"},
+		MaxInstructionChars: 100,
+	}, p)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "required_target: [claude]\n", "required_target"},
+		{"unknown target", "required_targets: [notepad]\n", `unknown target "notepad"`},
+		{"required and forbidden", "required_targets: [claude]\nforbidden_targets: [claude]\n", "both required and forbidden"},
+		{"absolute overlay", "required_overlays: [/etc/base.md]\n", "relative to the repository root"},
+		{"negative limit", "max_instruction_tokens: -1\n", "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yml")
+			writeFile(t, path, tt.content)
+
+			_, err := Load(path, target.NewDefaultRegistry())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPolicy_Rules(t *testing.T) {
+	assert.Empty(t, (&Policy{}).Rules())
+	assert.Equal(t, []string{"policy.forbidden_targets", "policy.max_instruction_tokens"},
+		(&Policy{ForbiddenTargets: []string{"windsurf"}, MaxInstructionTokens: 100}).Rules())
+}
+
+func TestLoad_Missing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "policy.yml"), target.NewDefaultRegistry())
+	assert.ErrorContains(t, err, "policy not found")
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\n"+strings.Repeat("Use Go. ", 50)+"\n")
+	cfg := &config.Config{Targets: []string{"cursor", "windsurf"}, LocalOverlays: []string{"./base.md"}}
+
+	tests := []struct {
+		name      string
+		policy    Policy
+		wantRules []string
+		wantFile  string
+	}{
+		{"empty policy", Policy{}, nil, ""},
+		{"complies", Policy{RequiredTargets: []string{"cursor"}, RequiredOverlays: []string{"base.md"}, MaxInstructionChars: 10000}, nil, ""},
+		{"required target", Policy{RequiredTargets: []string{"claude", "cursor"}}, []string{"policy.required_targets"}, ""},
+		{"forbidden target", Policy{ForbiddenTargets: []string{"windsurf"}}, []string{"policy.forbidden_targets"}, ""},
+		{"required overlay", Policy{RequiredOverlays: []string{"docs/security.md"}}, []string{"policy.required_overlays"}, ""},
+		{"size", Policy{MaxInstructionTokens: 50}, []string{"policy.max_instruction_tokens", "policy.max_instruction_tokens"}, ".cursorrules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := Check(dir, cfg, target.NewDefaultRegistry(), &tt.policy, "policy.yml", "")
+			require.NoError(t, err)
+
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.FieldPath)
+				assert.Equal(t, config.SeverityError, v.Severity)
+			}
+			assert.Equal(t, tt.wantRules, rules)
+			if tt.wantFile != "" {
+				assert.Equal(t, tt.wantFile, violations[0].File)
+				assert.Contains(t, violations[0].Remediation, "policy.yml")
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Package stats measures how much context the composed instructions
+// consume, for the hub file and for each target's rendered output.
+package stats
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/sync"
+	"github.com/ailign/cli/internal/target"
+)
+
+// HeaderSource labels the managed header in per-overlay breakdowns.
+const HeaderSource = "(header)"
+
+// charsPerToken is the rough ratio used to approximate token counts.
+// Tokenizers differ per tool; for English prose and code, about four
+// characters per token is a common rule of thumb.
+const charsPerToken = 4
+
+// Figures holds size measurements of a piece of text.
+type Figures struct {
+	Bytes  int
+	Chars  int
+	Lines  int
+	Tokens int // approximate
+}
+
+// Measure returns the figures for text.
+func Measure(text string) Figures {
+	chars := utf8.RuneCountInString(text)
+	lines := strings.Count(text, "\n")
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		lines++
+	}
+	return Figures{
+		Bytes:  len(text),
+		Chars:  chars,
+		Lines:  lines,
+		Tokens: (chars + charsPerToken - 1) / charsPerToken,
+	}
+}
+
+// Part is the share of a file contributed by one source overlay, or by
+// the managed header (Source == HeaderSource).
+type Part struct {
+	Source string
+	Figures
+}
+
+// FileStats describes one output file.
+type FileStats struct {
+	Target string // target name; empty for the hub
+	Path   string // relative to the repository root
+	Figures
+	Limit int // character limit of the target's tool; 0 when unknown
+	Parts []Part
+}
+
+// OverLimit reports whether the file exceeds its known limit.
+func (f FileStats) OverLimit() bool {
+	return f.Limit > 0 && f.Chars > f.Limit
+}
+
+// Report holds statistics for the hub and every configured target.
+type Report struct {
+	Hub     FileStats
+	Targets []FileStats
+}
+
+// Compute composes the configured overlays as sync would and measures
+// the hub and each target's content. Nothing is written. Targets the
+// registry doesn't know are skipped; config validation reports them.
+// version is the ailign version stamped into managed headers.
+func Compute(baseDir string, cfg *config.Config, registry *target.Registry, version string) (*Report, error) {
+	composed, err := sync.Compose(baseDir, cfg, version)
+	if err != nil {
+		return nil, err
+	}
+
+	header, segs := sync.HubSegments(composed)
+	report := &Report{
+		Hub: fileStats("", cfg.HubPath(), string(composed.Content), header, segs, cfg.LocalOverlays),
+	}
+
+	for _, name := range cfg.Targets {
+		tgt, ok := registry.Get(name)
+		if !ok {
+			continue
+		}
+		header, segs := sync.TargetSegments(composed, cfg, tgt)
+		fs := fileStats(name, filepath.ToSlash(tgt.InstructionPath()), string(sync.TargetContent(composed, cfg, tgt)), header, segs, cfg.LocalOverlays)
+		fs.Limit = cfg.SizeLimit(name, target.CharLimit(tgt))
+		report.Targets = append(report.Targets, fs)
+	}
+	return report, nil
+}
+
+// fileStats measures content and breaks it down by source. Every
+// configured overlay gets a part, in config order, even if it
+// contributed nothing to the output.
+func fileStats(name, p, content, header string, segs []sync.Segment, overlays []string) FileStats {
+	bySource := make(map[string]string, len(overlays))
+	for _, seg := range segs {
+		bySource[seg.Overlay] += seg.Text
+	}
+
+	fs := FileStats{Target: name, Path: p, Figures: Measure(content)}
+	if header != "" {
+		fs.Parts = append(fs.Parts, Part{Source: HeaderSource, Figures: Measure(header)})
+	}
+	for _, overlay := range overlays {
+		fs.Parts = append(fs.Parts, Part{Source: overlay, Figures: Measure(bySource[overlay])})
+	}
+	return fs
+}
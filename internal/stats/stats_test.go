@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		text string
+		want Figures
+	}{
+		{"", Figures{}},
+		{"abc\n", Figures{Bytes: 4, Chars: 4, Lines: 1, Tokens: 1}},
+		{"a\nb", Figures{Bytes: 3, Chars: 3, Lines: 2, Tokens: 1}},
+		{"héllo wörld\n", Figures{Bytes: 14, Chars: 12, Lines: 1, Tokens: 3}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Measure(tt.text), "text %q", tt.text)
+	}
+}
+
+func TestCompute_BreaksDownBySource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse gofmt.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "# Repo\n\nRun make test before pushing.\n")
+	cfg := &config.Config{Targets: []string{"claude", "windsurf"}, LocalOverlays: []string{"base.md", "repo.md"}}
+
+	report, err := Compute(dir, cfg, target.NewDefaultRegistry(), "")
+	require.NoError(t, err)
+
+	hub := report.Hub
+	assert.Equal(t, ".ailign/instructions.md", hub.Path)
+	assert.Empty(t, hub.Target)
+	require.Len(t, hub.Parts, 3)
+	assert.Equal(t, HeaderSource, hub.Parts[0].Source)
+	assert.Equal(t, "base.md", hub.Parts[1].Source)
+	assert.Equal(t, "repo.md", hub.Parts[2].Source)
+
+	sum := 0
+	for _, p := range hub.Parts {
+		sum += p.Bytes
+	}
+	assert.Equal(t, hub.Bytes, sum, "parts add up to the file")
+
+	require.Len(t, report.Targets, 2)
+	assert.Equal(t, "claude", report.Targets[0].Target)
+	assert.Equal(t, 0, report.Targets[0].Limit)
+	assert.Equal(t, hub.Figures, report.Targets[0].Figures, "symlinked targets read the hub")
+	assert.Equal(t, "windsurf", report.Targets[1].Target)
+	assert.Equal(t, 6000, report.Targets[1].Limit)
+	assert.False(t, report.Targets[1].OverLimit())
+}
+
+func TestCompute_CopyModeMeasuresRenderedTarget(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "docs", "guide.md"), "# Guide\n")
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nSee [guide](docs/guide.md).\n")
+	cfg := &config.Config{Targets: []string{"windsurf"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+
+	report, err := Compute(dir, cfg, target.NewDefaultRegistry(), "")
+	require.NoError(t, err)
+
+	ws := report.Targets[0]
+	assert.Less(t, ws.Parts[0].Bytes, report.Hub.Parts[0].Bytes, "plain header is shorter than the HTML comment")
+	assert.Less(t, ws.Parts[1].Bytes, report.Hub.Parts[1].Bytes, "link from the root is shorter than from .ailign/")
+	sum := 0
+	for _, p := range ws.Parts {
+		sum += p.Bytes
+	}
+	assert.Equal(t, ws.Bytes, sum)
+}
+
+func TestCompute_LimitOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\n"+strings.Repeat("Keep it short.\n", 20))
+	cfg := &config.Config{
+		Targets:       []string{"claude"},
+		LocalOverlays: []string{"base.md"},
+		Lint:          &config.LintConfig{SizeLimits: map[string]int{"claude": 100}},
+	}
+
+	report, err := Compute(dir, cfg, target.NewDefaultRegistry(), "")
+	require.NoError(t, err)
+	assert.Equal(t, 100, report.Targets[0].Limit)
+	assert.True(t, report.Targets[0].OverLimit())
+}
+
+func TestCompute_ComposeError(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"missing.md"}}
+
+	_, err := Compute(dir, cfg, target.NewDefaultRegistry(), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.md")
+}
@@ -7,21 +7,56 @@ import (
 	"path/filepath"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/ailign/cli/internal/config"
 )
 
 // ComposeOverlays reads and composes overlay files in order,
 // prepending a managed-content header. All overlays are validated
-// before composition; errors are collected and returned together.
-func ComposeOverlays(baseDir string, overlays []string) (*ComposeResult, error) {
+// before composition; errors are collected and returned together,
+// as an *InputError unless an overlay could not be read.
+//
+// Overlays are parsed into heading-delimited sections. A later overlay
+// can replace, append to, or delete a section from an earlier one by
+// placing an "<!-- ailign:replace -->", "<!-- ailign:append -->" or
+// "<!-- ailign:delete -->" comment directly above a matching heading.
+// Applied directives are recorded in ComposeResult.Operations.
+//
+// Duplicate overlays, sections repeated under the same heading and
+// paragraphs repeated verbatim across overlays are reported as warnings
+// with both source locations. With opts.Dedupe, later duplicates are
+// dropped from the output; conflicting sections are always kept.
+//
+// Relative links and images, written relative to each overlay, are
+// rewritten to resolve from opts.OutputDir. Links to files that don't
+// exist produce a warning.
+//
+// Each overlay is checked for hidden Unicode characters (bidi controls,
+// zero-width and tag characters), which fail composition unless
+// opts.HiddenUnicode lowers them to warnings or turns the check off.
+// Prompt injection heuristics run only when opts.PromptInjection is set.
+//
+// The composed body is scanned for credential patterns and high-entropy
+// strings; findings are recorded in ComposeResult.Secrets for the caller
+// to act on.
+func ComposeOverlays(baseDir string, overlays []string, opts ComposeOptions) (*ComposeResult, error) {
 	result := &ComposeResult{
 		Warnings: make([]string, 0),
 	}
 
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = "."
+	}
+
 	var errs []error
-	var parts []string
+	ioFailure := false // a read failed for reasons other than the input itself
+	var docs []*document
+	var contents []string
+	var sources []overlaySource
 
 	for _, overlay := range overlays {
-		if err := validateOverlayPath(baseDir, overlay); err != nil {
+		if err := ValidateOverlayPath(baseDir, overlay); err != nil {
 			errs = append(errs, err)
 			continue
 		}
@@ -33,6 +68,7 @@ func ComposeOverlays(baseDir string, overlays []string) (*ComposeResult, error)
 				errs = append(errs, fmt.Errorf("overlay file not found: %s", overlay))
 			} else {
 				errs = append(errs, fmt.Errorf("reading overlay %s: %w", overlay, err))
+				ioFailure = true
 			}
 			continue
 		}
@@ -43,27 +79,83 @@ func ComposeOverlays(baseDir string, overlays []string) (*ComposeResult, error)
 		}
 
 		content := string(data)
+		sources = append(sources, overlaySource{name: overlay, content: content})
+
+		for _, check := range []struct {
+			severity string
+			find     func(overlay, content string) []string
+		}{
+			{severityOr(opts.HiddenUnicode, config.SeverityError), findHiddenRunes},
+			{severityOr(opts.PromptInjection, config.SeverityOff), findInjections},
+		} {
+			if check.severity == config.SeverityOff {
+				continue
+			}
+			for _, msg := range check.find(overlay, content) {
+				if check.severity == config.SeverityError {
+					errs = append(errs, errors.New(msg))
+				} else {
+					result.Warnings = append(result.Warnings, msg)
+				}
+			}
+		}
 		if len(strings.TrimSpace(content)) == 0 {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("overlay %s is empty", overlay))
 		}
 
-		parts = append(parts, content)
+		rewritten, linkWarnings := rewriteOverlayLinks(baseDir, overlay, content, outputDir)
+		result.Warnings = append(result.Warnings, linkWarnings...)
+
+		doc, err := parseMarkdown(overlay, rewritten)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		docs = append(docs, doc)
+		contents = append(contents, content)
 	}
 
 	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
+		if ioFailure {
+			return nil, errors.Join(errs...)
+		}
+		return nil, &InputError{Err: errors.Join(errs...)}
 	}
 
-	header := buildHeader(overlays)
-	composed := header + strings.Join(parts, "\n")
-	result.Content = []byte(composed)
+	docs, dupWarnings := dedupeOverlays(docs, contents, opts.Dedupe)
+	result.Warnings = append(result.Warnings, dupWarnings...)
+
+	ops, err := applySectionDirectives(docs)
+	if err != nil {
+		return nil, &InputError{Err: err}
+	}
+	result.Operations = ops
+	result.Warnings = append(result.Warnings, detectDuplicates(docs, opts.Dedupe)...)
+
+	segments := renderSegments(docs)
+	body := joinSegments(segments)
+	result.Sources = overlays
+	result.Segments = segments
+	result.Body = []byte(body)
+	result.Secrets = scanSecrets(body, sources)
+	result.Version = opts.Version
+	result.SourcesDigest = sourcesDigest(sources)
+	result.Content = []byte(buildHeader(overlays, opts.Header, result.stamp(body)) + body)
 
 	return result, nil
 }
 
-// validateOverlayPath checks that an overlay path doesn't escape the base directory,
+// severityOr returns sev, or def when sev is unset.
+func severityOr(sev, def string) string {
+	if sev == "" {
+		return def
+	}
+	return sev
+}
+
+// ValidateOverlayPath checks that an overlay path doesn't escape the base directory,
 // both lexically and after resolving symlinks.
-func validateOverlayPath(baseDir, overlay string) error {
+func ValidateOverlayPath(baseDir, overlay string) error {
 	if filepath.IsAbs(overlay) || filepath.VolumeName(overlay) != "" {
 		return fmt.Errorf("overlay path must be relative to base directory: %s", overlay)
 	}
@@ -102,9 +194,3 @@ func validateOverlayPath(baseDir, overlay string) error {
 
 	return nil
 }
-
-// buildHeader creates the managed-content header for the hub file.
-func buildHeader(sources []string) string {
-	return fmt.Sprintf("<!-- DO NOT EDIT — Generated by ailign\n   Source: %s\n   Regenerate: ailign sync\n-->\n\n",
-		strings.Join(sources, ", "))
-}
@@ -18,7 +18,7 @@ func TestComposeOverlays_SingleFile(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, filepath.Join(dir, "base.md"), "# Base Instructions\n\nBe helpful.\n")
 
-	result, err := ComposeOverlays(dir, []string{"base.md"})
+	result, err := ComposeOverlays(dir, []string{"base.md"}, ComposeOptions{})
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	assert.Contains(t, string(result.Content), "# Base Instructions")
@@ -30,7 +30,7 @@ func TestComposeOverlays_MultipleFiles(t *testing.T) {
 	writeFile(t, filepath.Join(dir, "base.md"), "Base content.\n")
 	writeFile(t, filepath.Join(dir, "project.md"), "Project content.\n")
 
-	result, err := ComposeOverlays(dir, []string{"base.md", "project.md"})
+	result, err := ComposeOverlays(dir, []string{"base.md", "project.md"}, ComposeOptions{})
 	require.NoError(t, err)
 
 	content := string(result.Content)
@@ -45,7 +45,7 @@ func TestComposeOverlays_PrependsHeader(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, filepath.Join(dir, "base.md"), "Content\n")
 
-	result, err := ComposeOverlays(dir, []string{"base.md"})
+	result, err := ComposeOverlays(dir, []string{"base.md"}, ComposeOptions{})
 	require.NoError(t, err)
 
 	content := string(result.Content)
@@ -59,7 +59,7 @@ func TestComposeOverlays_HeaderContainsSources(t *testing.T) {
 	writeFile(t, filepath.Join(dir, "a.md"), "A\n")
 	writeFile(t, filepath.Join(dir, "b.md"), "B\n")
 
-	result, err := ComposeOverlays(dir, []string{"a.md", "b.md"})
+	result, err := ComposeOverlays(dir, []string{"a.md", "b.md"}, ComposeOptions{})
 	require.NoError(t, err)
 
 	content := string(result.Content)
@@ -70,7 +70,7 @@ func TestComposeOverlays_HeaderContainsSources(t *testing.T) {
 func TestComposeOverlays_PathTraversalRejected(t *testing.T) {
 	dir := t.TempDir()
 
-	_, err := ComposeOverlays(dir, []string{"../../etc/passwd"})
+	_, err := ComposeOverlays(dir, []string{"../../etc/passwd"}, ComposeOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "traversal")
 }
@@ -80,7 +80,7 @@ func TestComposeOverlays_NonUTF8Rejected(t *testing.T) {
 	// Write binary content (invalid UTF-8)
 	require.NoError(t, os.WriteFile(filepath.Join(dir, "binary.md"), []byte{0xFF, 0xFE, 0x00, 0x01}, 0644))
 
-	_, err := ComposeOverlays(dir, []string{"binary.md"})
+	_, err := ComposeOverlays(dir, []string{"binary.md"}, ComposeOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "UTF-8")
 }
@@ -89,7 +89,7 @@ func TestComposeOverlays_EmptyFileWarning(t *testing.T) {
 	dir := t.TempDir()
 	writeFile(t, filepath.Join(dir, "empty.md"), "")
 
-	result, err := ComposeOverlays(dir, []string{"empty.md"})
+	result, err := ComposeOverlays(dir, []string{"empty.md"}, ComposeOptions{})
 	require.NoError(t, err)
 	require.NotEmpty(t, result.Warnings)
 	assert.Contains(t, result.Warnings[0], "empty")
@@ -98,7 +98,7 @@ func TestComposeOverlays_EmptyFileWarning(t *testing.T) {
 func TestComposeOverlays_MissingFile(t *testing.T) {
 	dir := t.TempDir()
 
-	_, err := ComposeOverlays(dir, []string{"nonexistent.md"})
+	_, err := ComposeOverlays(dir, []string{"nonexistent.md"}, ComposeOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
@@ -0,0 +1,55 @@
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckCopyStatus returns what EnsureCopy would do without modifying any files.
+// Returns "created" (doesn't exist), "exists" (identical regular file), or "replaced" (different content, or a symlink or other entry at path).
+func CheckCopyStatus(path string, content []byte) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path must be absolute, got: %s", path)
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "created", nil
+		}
+		return "", fmt.Errorf("checking existing path: %w", err)
+	}
+
+	if info.Mode().IsRegular() {
+		existing, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading existing file: %w", err)
+		}
+		if bytes.Equal(existing, content) {
+			return "exists", nil
+		}
+	}
+
+	return "replaced", nil
+}
+
+// EnsureCopy writes content to path as a regular file, replacing any
+// symlink left behind by symlink mode. The path must be absolute.
+// Returns status: "created", "exists" (already identical), "replaced".
+func EnsureCopy(path string, content []byte) (string, error) {
+	status, err := CheckCopyStatus(path, content)
+	if err != nil {
+		return "", err
+	}
+	if status == "exists" {
+		return status, nil
+	}
+
+	if err := writeFileAtomic(path, content); err != nil {
+		return "", err
+	}
+	return status, nil
+}
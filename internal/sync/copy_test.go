@@ -0,0 +1,77 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Copy-mode target tests
+// ---------------------------------------------------------------------------
+
+func TestEnsureCopy_CreatesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".github", "copilot-instructions.md")
+
+	status, err := EnsureCopy(path, []byte("content"))
+	require.NoError(t, err)
+	assert.Equal(t, "created", status)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+}
+
+func TestEnsureCopy_IdenticalIsExists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".cursorrules")
+	writeFile(t, path, "content")
+
+	status, err := EnsureCopy(path, []byte("content"))
+	require.NoError(t, err)
+	assert.Equal(t, "exists", status)
+}
+
+func TestEnsureCopy_ReplacesSymlink(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	hub := filepath.Join(dir, ".ailign", "instructions.md")
+	writeFile(t, hub, "hub content")
+	path := filepath.Join(dir, ".cursorrules")
+	require.NoError(t, os.Symlink(".ailign/instructions.md", path))
+
+	status, err := EnsureCopy(path, []byte("hub content"))
+	require.NoError(t, err)
+	assert.Equal(t, "replaced", status)
+
+	info, err := os.Lstat(path)
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular(), "symlink should be replaced by a regular file")
+
+	data, err := os.ReadFile(hub)
+	require.NoError(t, err)
+	assert.Equal(t, "hub content", string(data), "the hub must not be written through the old symlink")
+}
+
+func TestCheckCopyStatus_DoesNotWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".cursorrules")
+	writeFile(t, path, "old")
+
+	status, err := CheckCopyStatus(path, []byte("new"))
+	require.NoError(t, err)
+	assert.Equal(t, "replaced", status)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+}
+
+func TestCheckCopyStatus_RelativePathRejected(t *testing.T) {
+	_, err := CheckCopyStatus(".cursorrules", []byte("x"))
+	require.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ailign/cli/internal/markdown"
+)
+
+// paragraph is a run of non-blank lines inside a block. Fenced code
+// blocks are kept whole, even when they contain blank lines.
+type paragraph struct {
+	blk   *block
+	start int // byte offset of the paragraph in blk.Text
+	end   int // byte offset one past the paragraph and its trailing blank lines
+	line  int // 1-based line in blk.Source
+	norm  string
+}
+
+// location formats a source location as "file:line".
+func location(source string, line int) string {
+	return fmt.Sprintf("%s:%d", source, line)
+}
+
+// dedupeOverlays reports (and with dedupe, drops) overlays whose content
+// is byte-identical to an earlier overlay. Empty overlays are ignored;
+// they are already reported separately.
+func dedupeOverlays(docs []*document, contents []string, dedupe bool) ([]*document, []string) {
+	var warnings []string
+	seen := make(map[string]string)
+	kept := make([]*document, 0, len(docs))
+
+	for i, doc := range docs {
+		content := contents[i]
+		if strings.TrimSpace(content) == "" {
+			kept = append(kept, doc)
+			continue
+		}
+		first, dup := seen[content]
+		if !dup {
+			seen[content] = doc.Source
+			kept = append(kept, doc)
+			continue
+		}
+		if dedupe {
+			warnings = append(warnings, fmt.Sprintf("removed duplicate overlay %s (identical to %s)", doc.Source, first))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("overlay %s is identical to %s", doc.Source, first))
+		doc.duplicate = true
+		kept = append(kept, doc)
+	}
+	return kept, warnings
+}
+
+// detectDuplicates reports sections that appear under the same heading in
+// more than one overlay, and paragraphs repeated verbatim across overlays.
+// With dedupe, later identical sections and paragraphs are removed; sections
+// whose content conflicts are always kept and reported.
+func detectDuplicates(docs []*document, dedupe bool) []string {
+	var warnings []string
+	skip := make(map[*block]bool)
+
+	type sectionRef struct {
+		blk  *block
+		norm string
+	}
+	firstSection := make(map[string]sectionRef)
+
+	for _, doc := range docs {
+		if doc.duplicate {
+			continue
+		}
+		for i := 0; i < len(doc.Blocks); i++ {
+			b := doc.Blocks[i]
+			if b.Level == 0 {
+				continue
+			}
+			end := sectionEnd(doc.Blocks, i)
+			norm := normalizeBlocks(doc.Blocks[i:end])
+			key := fmt.Sprintf("%d:%s", b.Level, strings.ToLower(b.Title))
+
+			ref, ok := firstSection[key]
+			if !ok {
+				firstSection[key] = sectionRef{blk: b, norm: norm}
+				continue
+			}
+			prev := ref.blk
+			if prev.Source == b.Source {
+				continue
+			}
+
+			if norm != ref.norm {
+				warnings = append(warnings, fmt.Sprintf("conflicting section %q in %s and %s (content differs)",
+					b.heading(), location(prev.Source, prev.Line), location(b.Source, b.Line)))
+				continue
+			}
+
+			for _, s := range doc.Blocks[i:end] {
+				skip[s] = true
+			}
+			if dedupe {
+				warnings = append(warnings, fmt.Sprintf("removed duplicate section %q at %s (identical to %s)",
+					b.heading(), location(b.Source, b.Line), location(prev.Source, prev.Line)))
+				doc.Blocks = splice(doc.Blocks, i, end, nil)
+				i--
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("duplicate section %q in %s and %s (identical content)",
+				b.heading(), location(prev.Source, prev.Line), location(b.Source, b.Line)))
+		}
+	}
+
+	firstParagraph := make(map[string]paragraph)
+	removals := make(map[*block][]paragraph)
+	for _, doc := range docs {
+		if doc.duplicate {
+			continue
+		}
+		for _, b := range doc.Blocks {
+			if skip[b] {
+				continue
+			}
+			for _, p := range paragraphs(b) {
+				if !isSubstantive(p.norm) {
+					continue
+				}
+				prev, ok := firstParagraph[p.norm]
+				if !ok {
+					firstParagraph[p.norm] = p
+					continue
+				}
+				if prev.blk.Source == p.blk.Source {
+					continue
+				}
+				if dedupe {
+					warnings = append(warnings, fmt.Sprintf("removed duplicate paragraph at %s (identical to %s)",
+						location(p.blk.Source, p.line), location(prev.blk.Source, prev.line)))
+					removals[b] = append(removals[b], p)
+					continue
+				}
+				warnings = append(warnings, fmt.Sprintf("duplicate paragraph in %s and %s",
+					location(prev.blk.Source, prev.line), location(p.blk.Source, p.line)))
+			}
+		}
+	}
+
+	for b, ps := range removals {
+		sort.Slice(ps, func(i, j int) bool { return ps[i].start > ps[j].start })
+		for _, p := range ps {
+			b.Text = b.Text[:p.start] + b.Text[p.end:]
+		}
+	}
+
+	return warnings
+}
+
+// paragraphs splits a block's body into paragraphs.
+func paragraphs(b *block) []paragraph {
+	var out []paragraph
+	lines := markdown.SplitLines(b.Text)
+	offset, line := 0, b.Line
+	if b.Level > 0 && len(lines) > 0 {
+		offset, line = len(lines[0]), line+1
+		lines = lines[1:]
+	}
+
+	var cur *paragraph
+	var text strings.Builder
+	var fence string
+	closed := false
+	flush := func() {
+		if cur != nil {
+			cur.norm = strings.Join(strings.Fields(text.String()), " ")
+			out = append(out, *cur)
+		}
+		cur, closed = nil, false
+		text.Reset()
+	}
+
+	for _, l := range lines {
+		trimmed := strings.TrimRight(l, "\r\n")
+		if fence == "" && strings.TrimSpace(trimmed) == "" {
+			if cur != nil {
+				cur.end = offset + len(l)
+				closed = true
+			}
+			offset, line = offset+len(l), line+1
+			continue
+		}
+
+		if closed {
+			flush()
+		}
+		if fence != "" {
+			if markdown.IsFenceClose(trimmed, fence) {
+				fence = ""
+			}
+		} else if f := markdown.FenceOpener(trimmed); f != "" {
+			fence = f
+		}
+
+		if cur == nil {
+			cur = &paragraph{blk: b, start: offset, line: line}
+		}
+		text.WriteString(l)
+		offset, line = offset+len(l), line+1
+		cur.end = offset
+	}
+	flush()
+	return out
+}
+
+// normalizeBlocks returns the whitespace-normalized body of a section,
+// used to compare sections regardless of formatting differences.
+func normalizeBlocks(blocks []*block) string {
+	var b strings.Builder
+	b.WriteString(blocks[0].body())
+	for _, blk := range blocks[1:] {
+		b.WriteString("\n")
+		b.WriteString(blk.Text)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// isSubstantive reports whether a normalized paragraph is worth comparing.
+// Thematic breaks, bare list markers and very short fragments repeat
+// legitimately and would only produce noise.
+func isSubstantive(norm string) bool {
+	letters := 0
+	for _, r := range norm {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 12
+}
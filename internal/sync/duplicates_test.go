@@ -0,0 +1,121 @@
+package sync
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Duplicate and conflict detection tests
+// ---------------------------------------------------------------------------
+
+func TestComposeOverlays_ConflictingSectionWarning(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\n## Testing\n\nUse mocks everywhere.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "## Testing\n\nNever use mocks in this repository.\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "conflicting section \"## Testing\"")
+	assert.Contains(t, result.Warnings[0], "base.md:3")
+	assert.Contains(t, result.Warnings[0], "repo.md:1")
+}
+
+func TestComposeOverlays_DuplicateSectionWarning(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "## Testing\n\nRun go test before pushing.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "# Repo\n\n## Testing\n\nRun  go test before pushing.\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1, "the duplicate paragraph is not reported again")
+	assert.Contains(t, result.Warnings[0], "duplicate section \"## Testing\" in base.md:1 and repo.md:3")
+}
+
+func TestComposeOverlays_DuplicateParagraphWarning(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nAlways write tests first.\n\nUse tabs.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "# Repo\n\nPrefer small PRs.\n\nAlways write\ntests first.\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "duplicate paragraph in base.md:3 and repo.md:5", result.Warnings[0])
+}
+
+func TestComposeOverlays_ShortParagraphsIgnored(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# A\n\n---\n\nSee below.\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "# B\n\n---\n\nSee below.\n")
+
+	result, err := ComposeOverlays(dir, []string{"a.md", "b.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestComposeOverlays_DuplicateOverlayWarning(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "## Testing\n\nRun go test before pushing.\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "## Testing\n\nRun go test before pushing.\n")
+
+	result, err := ComposeOverlays(dir, []string{"a.md", "b.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "overlay b.md is identical to a.md", result.Warnings[0])
+	assert.Equal(t, 2, strings.Count(string(result.Content), "Run go test"))
+}
+
+func TestComposeOverlays_Dedupe(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "# Base\n\nAlways write tests first.\n\n## Style\n\nUse gofmt on every file.\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "# Repo\n\nAlways write tests first.\n\nKeep functions small.\n\n## Style\n\nUse gofmt on every file.\n")
+	writeFile(t, filepath.Join(dir, "c.md"), "# Base\n\nAlways write tests first.\n\n## Style\n\nUse gofmt on every file.\n")
+
+	result, err := ComposeOverlays(dir, []string{"a.md", "b.md", "c.md"}, ComposeOptions{Dedupe: true})
+	require.NoError(t, err)
+
+	content := string(result.Content)
+	assert.Equal(t, 1, strings.Count(content, "Always write tests first."))
+	assert.Equal(t, 1, strings.Count(content, "Use gofmt on every file."))
+	assert.Equal(t, 1, strings.Count(content, "## Style"))
+	assert.Contains(t, content, "Keep functions small.")
+
+	require.Len(t, result.Warnings, 3)
+	assert.Equal(t, "removed duplicate overlay c.md (identical to a.md)", result.Warnings[0])
+	assert.Contains(t, result.Warnings[1], "removed duplicate section \"## Style\" at b.md:7")
+	assert.Equal(t, "removed duplicate paragraph at b.md:3 (identical to a.md:3)", result.Warnings[2])
+}
+
+func TestComposeOverlays_DedupeKeepsConflicts(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "## Testing\n\nUse mocks everywhere.\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "## Testing\n\nNever use mocks at all.\n")
+
+	result, err := ComposeOverlays(dir, []string{"a.md", "b.md"}, ComposeOptions{Dedupe: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(result.Content), "Use mocks everywhere.")
+	assert.Contains(t, string(result.Content), "Never use mocks at all.")
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "conflicting section")
+}
+
+// ---------------------------------------------------------------------------
+// paragraphs tests
+// ---------------------------------------------------------------------------
+
+func TestParagraphs_KeepsFencesWhole(t *testing.T) {
+	b := &block{Level: 1, Title: "A", Line: 1, Text: "# A\n\nIntro text.\n\n```\ncode\n\nmore code\n```\n\nOutro.\n"}
+
+	ps := paragraphs(b)
+	require.Len(t, ps, 3)
+	assert.Equal(t, 3, ps[0].line)
+	assert.Equal(t, "``` code more code ```", ps[1].norm)
+	assert.Equal(t, 5, ps[1].line)
+	assert.Equal(t, "Outro.", ps[2].norm)
+	assert.Equal(t, 11, ps[2].line)
+}
@@ -0,0 +1,13 @@
+package sync
+
+// InputError reports configuration or overlays that can't be synced as
+// they are: missing or malformed overlays, unsafe content, an invalid
+// hub path or target selection. Fixing the input, not retrying, is
+// what resolves it.
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string { return e.Err.Error() }
+
+func (e *InputError) Unwrap() error { return e.Err }
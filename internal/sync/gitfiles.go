@@ -0,0 +1,196 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+)
+
+// Files that record how git treats the outputs.
+const (
+	GitignorePath     = ".gitignore"
+	GitattributesPath = ".gitattributes"
+)
+
+// The ailign block in .gitignore or .gitattributes sits between these
+// lines. Lines outside it belong to the user and are never changed.
+const (
+	blockBegin = `# BEGIN ailign (managed by "ailign sync"; edits inside this block are overwritten)`
+	blockEnd   = "# END ailign"
+)
+
+// GitFileResult reports what sync did to the ailign block of
+// .gitignore or .gitattributes.
+type GitFileResult struct {
+	Path   string // relative to the repository root, like LinkResult.LinkPath
+	Status string // "written", "unchanged", "removed", "rolled_back", "error"
+	Error  string
+}
+
+// gitFileBlock is the ailign block a git file should hold.
+type gitFileBlock struct {
+	path  string // relative to the repository root
+	lines []string
+}
+
+// gitFileBlocks returns the ailign blocks for .gitignore and
+// .gitattributes that match cfg.CommitOutputs: false ignores the hub
+// and every target path, true marks them linguist-generated, and unset
+// leaves both blocks empty.
+func gitFileBlocks(cfg *config.Config, registry *target.Registry) []gitFileBlock {
+	paths := outputPatterns(cfg, registry)
+	var ignore, attributes []string
+	if cfg.CommitOutputs != nil {
+		if *cfg.CommitOutputs {
+			for _, p := range paths {
+				attributes = append(attributes, p+" linguist-generated")
+			}
+		} else {
+			ignore = paths
+		}
+	}
+	return []gitFileBlock{
+		{GitignorePath, ignore},
+		{GitattributesPath, attributes},
+	}
+}
+
+// syncGitFiles brings the ailign blocks in .gitignore and .gitattributes
+// in line with gitFileBlocks, removing a block that should be empty.
+// Only files whose block changed, or failed to, are reported.
+func syncGitFiles(baseDir string, cfg *config.Config, registry *target.Registry, dryRun bool) []GitFileResult {
+	var results []GitFileResult
+	for _, f := range gitFileBlocks(cfg, registry) {
+		r := GitFileResult{Path: f.path}
+		status, err := updateBlock(filepath.Join(baseDir, f.path), f.lines, dryRun)
+		if err != nil {
+			r.Status = "error"
+			r.Error = err.Error()
+		} else {
+			r.Status = status
+		}
+		if r.Status != "unchanged" {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// gitFileSteps returns a step per git file for applyAtomically, and the
+// result each step reports into.
+func gitFileSteps(baseDir string, cfg *config.Config, registry *target.Registry) ([]syncStep, []GitFileResult) {
+	var steps []syncStep
+	var results []GitFileResult
+	for _, f := range gitFileBlocks(cfg, registry) {
+		path, lines := filepath.Join(baseDir, f.path), f.lines
+		steps = append(steps, syncStep{
+			path:  path,
+			check: func() (string, error) { return updateBlock(path, lines, true) },
+			apply: func() (string, error) { return updateBlock(path, lines, false) },
+		})
+		results = append(results, GitFileResult{Path: f.path})
+	}
+	return steps, results
+}
+
+// outputPatterns returns the hub and the configured targets' paths as
+// patterns anchored at the repository root.
+func outputPatterns(cfg *config.Config, registry *target.Registry) []string {
+	patterns := []string{"/" + cfg.HubPath()}
+	for _, name := range cfg.Targets {
+		if tgt, ok := registry.Get(name); ok {
+			patterns = append(patterns, "/"+filepath.ToSlash(tgt.InstructionPath()))
+		}
+	}
+	return patterns
+}
+
+// updateBlock replaces the ailign block in the file at path with lines,
+// appending it if there is none. An empty lines removes the block, and
+// the file too if nothing else is left in it.
+func updateBlock(path string, lines []string, dryRun bool) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	old := string(data)
+
+	eol := "\n"
+	if strings.Contains(old, "\r\n") {
+		eol = "\r\n"
+	}
+	before, after, found, err := cutBlock(old)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	var updated, status string
+	switch {
+	case len(lines) == 0 && !found:
+		return "unchanged", nil
+	case len(lines) == 0:
+		if after == "" && strings.HasSuffix(before, eol+eol) {
+			// Drop the blank line that kept the block apart
+			before = strings.TrimSuffix(before, eol)
+		}
+		updated, status = before+after, "removed"
+	default:
+		block := blockBegin + eol + strings.Join(lines, eol) + eol + blockEnd + eol
+		if !found {
+			// Keep the block apart from the user's lines
+			if before != "" && !strings.HasSuffix(before, "\n") {
+				before += eol
+			}
+			if before != "" && !strings.HasSuffix(before, eol+eol) {
+				before += eol
+			}
+		}
+		updated, status = before+block+after, "written"
+	}
+	if updated == old {
+		return "unchanged", nil
+	}
+	if dryRun {
+		return status, nil
+	}
+
+	if updated == "" {
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("removing %s: %w", filepath.Base(path), err)
+		}
+		return status, nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return status, nil
+}
+
+// cutBlock splits content around the ailign block, returning the text
+// before and after it. A block that is opened but never closed is an
+// error: guessing where it ends could swallow the user's lines.
+func cutBlock(content string) (before, after string, found bool, err error) {
+	start := -1
+	offset := 0
+	for i, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case start < 0 && trimmed == blockBegin:
+			start = offset
+		case start >= 0 && trimmed == blockEnd:
+			return content[:start], content[offset+len(line):], true, nil
+		case start < 0 && trimmed == blockEnd:
+			return "", "", false, fmt.Errorf("line %d ends an ailign block that was never started; remove it", i+1)
+		}
+		offset += len(line)
+	}
+	if start >= 0 {
+		return "", "", false, fmt.Errorf("the ailign block starting with %q is not closed by %q; fix or remove it", blockBegin, blockEnd)
+	}
+	return content, "", false, nil
+}
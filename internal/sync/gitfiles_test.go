@@ -0,0 +1,168 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBlock(t *testing.T) {
+	block := blockBegin + "\n/a\n/b\n" + blockEnd + "\n"
+	tests := []struct {
+		name       string
+		existing   string // "" means no file
+		lines      []string
+		want       string // "" means the file is gone
+		wantStatus string
+	}{
+		{
+			name:       "creates the file",
+			lines:      []string{"/a", "/b"},
+			want:       block,
+			wantStatus: "written",
+		},
+		{
+			name:       "appends after user lines",
+			existing:   "node_modules/\n*.log",
+			lines:      []string{"/a", "/b"},
+			want:       "node_modules/\n*.log\n\n" + block,
+			wantStatus: "written",
+		},
+		{
+			name:       "replaces the block in place",
+			existing:   "# mine\n" + blockBegin + "\n/old\n" + blockEnd + "\ndist/\n",
+			lines:      []string{"/a", "/b"},
+			want:       "# mine\n" + block + "dist/\n",
+			wantStatus: "written",
+		},
+		{
+			name:       "unchanged block",
+			existing:   "dist/\n\n" + block,
+			lines:      []string{"/a", "/b"},
+			want:       "dist/\n\n" + block,
+			wantStatus: "unchanged",
+		},
+		{
+			name:       "removes the block and its separator",
+			existing:   "dist/\n\n" + block,
+			want:       "dist/\n",
+			wantStatus: "removed",
+		},
+		{
+			name:       "removes a file holding only the block",
+			existing:   block,
+			want:       "",
+			wantStatus: "removed",
+		},
+		{
+			name:       "nothing to remove",
+			existing:   "dist/\n",
+			want:       "dist/\n",
+			wantStatus: "unchanged",
+		},
+		{
+			name:       "keeps CRLF line endings",
+			existing:   "dist/\r\n",
+			lines:      []string{"/a"},
+			want:       "dist/\r\n\r\n" + blockBegin + "\r\n/a\r\n" + blockEnd + "\r\n",
+			wantStatus: "written",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".gitignore")
+			if tt.existing != "" {
+				writeFile(t, path, tt.existing)
+			}
+
+			status, err := updateBlock(path, tt.lines, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, status)
+
+			data, err := os.ReadFile(path)
+			if tt.want == "" {
+				assert.True(t, os.IsNotExist(err), "file should be removed")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestUpdateBlock_DryRunWritesNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	writeFile(t, path, "dist/\n")
+
+	status, err := updateBlock(path, []string{"/a"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "written", status)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "dist/\n", string(data))
+}
+
+func TestUpdateBlock_UnclosedBlockLeavesFileAlone(t *testing.T) {
+	for _, content := range []string{
+		"dist/\n" + blockBegin + "\n/a\n",
+		"dist/\n" + blockEnd + "\n",
+	} {
+		path := filepath.Join(t.TempDir(), ".gitignore")
+		writeFile(t, path, content)
+
+		_, err := updateBlock(path, []string{"/a"}, false)
+		assert.ErrorContains(t, err, "ailign block")
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+}
+
+func TestSync_CommitOutputs(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	writeFile(t, filepath.Join(dir, ".gitignore"), "node_modules/\n")
+	commit := false
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}, CommitOutputs: &commit}
+
+	result := syncForState(t, dir, cfg, SyncOptions{})
+	assert.Equal(t, []GitFileResult{{Path: ".gitignore", Status: "written"}}, result.GitFiles)
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules/\n\n"+blockBegin+"\n/.ailign/instructions.md\n/.claude/instructions.md\n/.cursorrules\n"+blockEnd+"\n", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, ".gitattributes"))
+
+	// An up-to-date run leaves the block alone
+	result = syncForState(t, dir, cfg, SyncOptions{})
+	assert.Empty(t, result.GitFiles)
+
+	// Committing the outputs moves them to .gitattributes
+	commit = true
+	result = syncForState(t, dir, cfg, SyncOptions{})
+	assert.ElementsMatch(t, []GitFileResult{
+		{Path: ".gitignore", Status: "removed"},
+		{Path: ".gitattributes", Status: "written"},
+	}, result.GitFiles)
+	data, err = os.ReadFile(filepath.Join(dir, ".gitignore"))
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules/\n", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, ".gitattributes"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "/.cursorrules linguist-generated\n")
+
+	// Unsetting the option removes the block again
+	cfg.CommitOutputs = nil
+	result = syncForState(t, dir, cfg, SyncOptions{DryRun: true})
+	assert.Equal(t, []GitFileResult{{Path: ".gitattributes", Status: "removed"}}, result.GitFiles)
+	assert.FileExists(t, filepath.Join(dir, ".gitattributes"))
+	syncForState(t, dir, cfg, SyncOptions{})
+	assert.NoFileExists(t, filepath.Join(dir, ".gitattributes"))
+}
@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ailign/cli/internal/target"
+)
+
+const defaultHeaderText = "DO NOT EDIT — Generated by ailign"
+
+// HeaderStamp is the provenance a managed header records: the ailign
+// version that wrote the file, a digest of the overlays it was composed
+// from, and a digest of the content below the header. The body digest
+// lets edits be detected without the state manifest.
+type HeaderStamp struct {
+	Version string
+	Sources string
+	Body    string
+}
+
+// buildHeader creates the managed-content header. Markdown files get an
+// HTML comment; plain-text files get "#"-prefixed lines, since an HTML
+// comment would be shown to the tool verbatim.
+func buildHeader(sources []string, opts HeaderOptions, stamp HeaderStamp) string {
+	text := opts.Text
+	if text == "" {
+		text = defaultHeaderText
+	}
+
+	lines := []string{text, "Source: " + strings.Join(sources, ", ")}
+	if opts.Link != "" {
+		lines = append(lines, "Docs: "+opts.Link)
+	}
+	lines = append(lines, "Regenerate: ailign sync")
+	lines = append(lines,
+		strings.TrimSpace("Generator: ailign "+stamp.Version),
+		"Sources-Digest: "+stamp.Sources,
+		"Body-Digest: "+stamp.Body)
+
+	if opts.Format == target.FormatPlain {
+		return "# " + strings.Join(lines, "\n# ") + "\n\n"
+	}
+	return "<!-- " + strings.Join(lines, "\n   ") + "\n-->\n\n"
+}
+
+// readHeaderStamp parses the stamp from the managed header at the start
+// of content and returns it with the content that follows the header.
+// Line endings are normalized first, so a checkout that converted them
+// still matches. ok is false if content has no stamped header.
+func readHeaderStamp(content string) (stamp HeaderStamp, body string, ok bool) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var header string
+	switch {
+	case strings.HasPrefix(content, "<!-- "):
+		var found bool
+		header, body, found = strings.Cut(content, "\n-->\n\n")
+		if !found {
+			return HeaderStamp{}, "", false
+		}
+	case strings.HasPrefix(content, "# "):
+		var found bool
+		header, body, found = strings.Cut(content, "\n\n")
+		if !found {
+			return HeaderStamp{}, "", false
+		}
+	default:
+		return HeaderStamp{}, "", false
+	}
+
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimLeft(strings.TrimPrefix(line, "<!--"), " #")
+		key, value, _ := strings.Cut(line, ": ")
+		switch key {
+		case "Generator":
+			stamp.Version = strings.TrimSpace(strings.TrimPrefix(value, "ailign"))
+		case "Sources-Digest":
+			stamp.Sources = value
+		case "Body-Digest":
+			stamp.Body = value
+		}
+	}
+	return stamp, body, stamp.Body != ""
+}
+
+// editedSinceStamped reports whether content carries a stamped header
+// whose body digest no longer matches the body below it.
+func editedSinceStamped(content string) bool {
+	stamp, body, ok := readHeaderStamp(content)
+	return ok && bodyDigest(body) != stamp.Body
+}
+
+// bodyDigest digests the content below a managed header. Line endings
+// are normalized as readHeaderStamp normalizes them, so an overlay with
+// CRLF line endings is stamped with the digest the reader computes.
+func bodyDigest(body string) string {
+	return contentDigest([]byte(strings.ReplaceAll(body, "\r\n", "\n")))
+}
+
+// sourcesDigest digests the overlays' paths and contents, in order.
+func sourcesDigest(sources []overlaySource) string {
+	h := sha256.New()
+	for _, s := range sources {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\n", s.name, contentDigest([]byte(s.content)))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// formatForPath infers the instruction format from a file extension.
+func formatForPath(p string) string {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".markdown", ".mdc", ".mdx":
+		return target.FormatMarkdown
+	default:
+		return target.FormatPlain
+	}
+}
@@ -0,0 +1,98 @@
+package sync
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Managed header tests
+// ---------------------------------------------------------------------------
+
+var testStamp = HeaderStamp{Version: "1.2.3", Sources: "sha256:aa", Body: "sha256:bb"}
+
+func TestBuildHeader_Default(t *testing.T) {
+	got := buildHeader([]string{"a.md", "b.md"}, HeaderOptions{}, testStamp)
+	assert.Equal(t, "<!-- DO NOT EDIT — Generated by ailign\n   Source: a.md, b.md\n   Regenerate: ailign sync\n"+
+		"   Generator: ailign 1.2.3\n   Sources-Digest: sha256:aa\n   Body-Digest: sha256:bb\n-->\n\n", got)
+}
+
+func TestBuildHeader_CustomTextAndLink(t *testing.T) {
+	got := buildHeader([]string{"a.md"}, HeaderOptions{Text: "Managed by Platform", Link: "https://wiki.example.com/ailign"}, testStamp)
+	assert.True(t, strings.HasPrefix(got, "<!-- Managed by Platform\n   Source: a.md\n   Docs: https://wiki.example.com/ailign\n   Regenerate: ailign sync\n"), got)
+}
+
+func TestBuildHeader_PlainFormat(t *testing.T) {
+	got := buildHeader([]string{"a.md"}, HeaderOptions{Format: target.FormatPlain}, HeaderStamp{Sources: "sha256:aa", Body: "sha256:bb"})
+	assert.Equal(t, "# DO NOT EDIT — Generated by ailign\n# Source: a.md\n# Regenerate: ailign sync\n"+
+		"# Generator: ailign\n# Sources-Digest: sha256:aa\n# Body-Digest: sha256:bb\n\n", got)
+	assert.NotContains(t, got, "<!--")
+}
+
+func TestReadHeaderStamp(t *testing.T) {
+	body := "# Base\n\nUse Go.\n"
+	for _, format := range []string{target.FormatMarkdown, target.FormatPlain} {
+		stamp := HeaderStamp{Version: "dev (none)", Sources: "sha256:aa", Body: contentDigest([]byte(body))}
+		content := buildHeader([]string{"a.md"}, HeaderOptions{Format: format}, stamp) + body
+
+		got, gotBody, ok := readHeaderStamp(content)
+		require.True(t, ok, format)
+		assert.Equal(t, stamp, got)
+		assert.Equal(t, body, gotBody)
+		assert.False(t, editedSinceStamped(content), format)
+		assert.False(t, editedSinceStamped(strings.ReplaceAll(content, "\n", "\r\n")), "line endings alone are not an edit")
+		assert.True(t, editedSinceStamped(content+"Hand edit.\n"), format)
+	}
+
+	// An overlay with CRLF line endings, stamped through compose
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\r\n\r\nUse Go.\r\n")
+	composed, err := ComposeOverlays(dir, []string{"base.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	for _, content := range []string{string(composed.Content), string(RenderTarget(composed, target.Cursor{}, RenderOptions{HubPath: ".ailign/instructions.md"}))} {
+		_, _, ok := readHeaderStamp(content)
+		require.True(t, ok, content)
+		assert.False(t, editedSinceStamped(content), content)
+		assert.True(t, editedSinceStamped(content+"Hand edit.\r\n"), content)
+	}
+
+	for _, content := range []string{"", "# Title\n\nNo header.\n", "<!-- unterminated\n"} {
+		_, _, ok := readHeaderStamp(content)
+		assert.False(t, ok, content)
+		assert.False(t, editedSinceStamped(content))
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{".ailign/instructions.md", target.FormatMarkdown},
+		{"AGENTS.MD", target.FormatMarkdown},
+		{".cursor/rules/main.mdc", target.FormatMarkdown},
+		{".ailign/instructions.txt", target.FormatPlain},
+		{".ailign/rules", target.FormatPlain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatForPath(tt.path))
+		})
+	}
+}
+
+func TestRenderTarget_HeaderFollowsTargetFormat(t *testing.T) {
+	composed := &ComposeResult{Body: []byte("Body\n"), Sources: []string{"base.md"}}
+	opts := RenderOptions{HubPath: ".ailign/instructions.md"}
+
+	assert.Contains(t, string(RenderTarget(composed, target.Claude{}, opts)), "<!-- DO NOT EDIT")
+	assert.Contains(t, string(RenderTarget(composed, target.Cursor{}, opts)), "# DO NOT EDIT")
+
+	opts.OmitHeader = true
+	assert.Equal(t, "Body\n", string(RenderTarget(composed, target.Cursor{}, opts)))
+}
@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ailign/cli/internal/markdown"
+)
+
+// hiddenRuneNames names the invisible code points that can make text
+// read differently to a reviewer than to a model: bidirectional
+// controls (Trojan Source), zero-width characters and the like.
+var hiddenRuneNames = map[rune]string{
+	0x00AD: "SOFT HYPHEN",
+	0x061C: "ARABIC LETTER MARK",
+	0x180E: "MONGOLIAN VOWEL SEPARATOR",
+	0x200B: "ZERO WIDTH SPACE",
+	0x200C: "ZERO WIDTH NON-JOINER",
+	0x200D: "ZERO WIDTH JOINER",
+	0x200E: "LEFT-TO-RIGHT MARK",
+	0x200F: "RIGHT-TO-LEFT MARK",
+	0x202A: "LEFT-TO-RIGHT EMBEDDING",
+	0x202B: "RIGHT-TO-LEFT EMBEDDING",
+	0x202C: "POP DIRECTIONAL FORMATTING",
+	0x202D: "LEFT-TO-RIGHT OVERRIDE",
+	0x202E: "RIGHT-TO-LEFT OVERRIDE",
+	0x2060: "WORD JOINER",
+	0x2061: "FUNCTION APPLICATION",
+	0x2062: "INVISIBLE TIMES",
+	0x2063: "INVISIBLE SEPARATOR",
+	0x2064: "INVISIBLE PLUS",
+	0x2066: "LEFT-TO-RIGHT ISOLATE",
+	0x2067: "RIGHT-TO-LEFT ISOLATE",
+	0x2068: "FIRST STRONG ISOLATE",
+	0x2069: "POP DIRECTIONAL ISOLATE",
+	0xFEFF: "ZERO WIDTH NO-BREAK SPACE",
+}
+
+// hiddenRuneName returns the name of r if it is a hidden character, or
+// "" if it is not. Unicode tag characters (U+E0000-U+E007F) can smuggle
+// an entire ASCII string invisibly and are always hidden.
+func hiddenRuneName(r rune) string {
+	if r >= 0xE0000 && r <= 0xE007F {
+		return "TAG CHARACTER"
+	}
+	return hiddenRuneNames[r]
+}
+
+// findHiddenRunes reports hidden characters in an overlay, one message
+// per affected line, listing each distinct code point with its column.
+// A leading byte order mark and zero width joiners inside emoji
+// sequences are legitimate and not reported.
+func findHiddenRunes(overlay, content string) []string {
+	var findings []string
+	for i, line := range markdown.SplitLines(content) {
+		var found []string
+		seen := make(map[rune]bool)
+		count := 0
+		col := 0
+		var prev rune
+		for j, r := range line {
+			col++
+			if i == 0 && j == 0 && r == 0xFEFF {
+				prev = r
+				continue
+			}
+			name := hiddenRuneName(r)
+			if name == "" || (r == 0x200D && isEmojiPart(prev)) {
+				prev = r
+				continue
+			}
+			count++
+			if !seen[r] {
+				seen[r] = true
+				found = append(found, fmt.Sprintf("U+%04X %s (column %d)", r, name, col))
+			}
+			prev = r
+		}
+		if count == 0 {
+			continue
+		}
+		msg := fmt.Sprintf("overlay %s:%d: hidden Unicode character %s", overlay, i+1, strings.Join(found, ", "))
+		if count > len(found) {
+			msg += fmt.Sprintf(", %d occurrences in total", count)
+		}
+		findings = append(findings, msg)
+	}
+	return findings
+}
+
+// isEmojiPart reports whether r can precede a zero width joiner in an
+// emoji sequence.
+func isEmojiPart(r rune) bool {
+	return unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) || r == 0xFE0F
+}
@@ -25,10 +25,6 @@ func CheckHubStatus(hubPath string, content []byte) (string, error) {
 }
 
 // WriteHub writes content to the hub file using write-temp-rename.
-// The temp file is fsynced before rename for crash safety; note that
-// the parent directory is not fsynced, so a power loss after rename
-// could lose the update on some filesystems. This is acceptable
-// because the hub file is regenerable via "ailign sync".
 // Returns "written" if content changed, "unchanged" if identical to existing.
 func WriteHub(hubPath string, content []byte) (string, error) {
 	// Check if existing content is identical
@@ -40,42 +36,56 @@ func WriteHub(hubPath string, content []byte) (string, error) {
 		return "unchanged", nil
 	}
 
+	if err := writeFileAtomic(hubPath, content); err != nil {
+		return "", err
+	}
+
+	return "written", nil
+}
+
+// writeFileAtomic writes content to path using write-temp-rename.
+// The temp file is fsynced before rename for crash safety; note that
+// the parent directory is not fsynced, so a power loss after rename
+// could lose the update on some filesystems. This is acceptable
+// because every file ailign writes is regenerable via "ailign sync".
+// If path is a symlink, the link itself is replaced, not its target.
+func writeFileAtomic(path string, content []byte) error {
 	// Create directory if needed
-	dir := filepath.Dir(hubPath)
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", fmt.Errorf("creating hub directory: %w", err)
+		return fmt.Errorf("creating directory: %w", err)
 	}
 
 	// Atomic write: temp file → chmod → fsync → rename
 	tmp, err := os.CreateTemp(dir, ".ailign-*.tmp")
 	if err != nil {
-		return "", fmt.Errorf("creating temp file: %w", err)
+		return fmt.Errorf("creating temp file: %w", err)
 	}
 	defer func() { _ = os.Remove(tmp.Name()) }() // cleanup on error path
 
-	// CreateTemp uses 0600; set 0644 so the hub file is world-readable
+	// CreateTemp uses 0600; set 0644 so the file is world-readable
 	if err := os.Chmod(tmp.Name(), 0644); err != nil {
 		_ = tmp.Close()
-		return "", fmt.Errorf("setting hub file permissions: %w", err)
+		return fmt.Errorf("setting file permissions: %w", err)
 	}
 
 	if _, err := tmp.Write(content); err != nil {
 		_ = tmp.Close()
-		return "", fmt.Errorf("writing temp file: %w", err)
+		return fmt.Errorf("writing temp file: %w", err)
 	}
 
 	if err := tmp.Sync(); err != nil {
 		_ = tmp.Close()
-		return "", fmt.Errorf("syncing temp file: %w", err)
+		return fmt.Errorf("syncing temp file: %w", err)
 	}
 
 	if err := tmp.Close(); err != nil {
-		return "", fmt.Errorf("closing temp file: %w", err)
+		return fmt.Errorf("closing temp file: %w", err)
 	}
 
-	if err := os.Rename(tmp.Name(), hubPath); err != nil {
-		return "", fmt.Errorf("renaming hub file: %w", err)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("renaming temp file: %w", err)
 	}
 
-	return "written", nil
+	return nil
 }
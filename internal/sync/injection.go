@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ailign/cli/internal/markdown"
+)
+
+// injectionRule is a heuristic for text that tries to subvert the tool
+// reading the instructions rather than guide it.
+type injectionRule struct {
+	description string
+	pattern     *regexp.Regexp
+}
+
+// injectionRules are deliberately narrow: they run over trusted,
+// reviewed content, so false positives cost more than they catch.
+var injectionRules = []injectionRule{
+	{
+		description: "instruction override",
+		pattern:     regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override)\s+(?:all\s+|any\s+)?(?:the\s+|your\s+)?(?:previous|prior|above|earlier|preceding|system)\s+(?:instructions|rules|prompts?|directions|guidelines)`),
+	},
+	{
+		description: "concealment from the user",
+		pattern:     regexp.MustCompile(`(?i)\b(?:do\s+not|don't|never)\s+(?:tell|inform|mention\s+(?:this\s+)?to|reveal\s+(?:this\s+)?to|show)\s+the\s+user\b`),
+	},
+	{
+		description: "system prompt extraction",
+		pattern:     regexp.MustCompile(`(?i)\b(?:reveal|print|output|repeat|send|leak)\s+(?:the\s+|your\s+)?(?:system\s+prompt|hidden\s+instructions|initial\s+instructions)`),
+	},
+	{
+		description: "exfiltration URL",
+		pattern:     regexp.MustCompile(`(?i)https?://[^\s)>"']*(?:webhook\.site|requestbin|pipedream\.net|ngrok(?:-free)?\.(?:io|app)|burpcollaborator\.net|interact\.sh|oast\.(?:fun|live|me|pro|site|online))`),
+	},
+	{
+		description: "exfiltration URL",
+		pattern:     regexp.MustCompile(`(?i)https?://[^\s)>"']*\?[^\s)>"']*(?:\$\{?[A-Z_]*(?:TOKEN|KEY|SECRET|PASSWORD)|\{\{[^}]*\}\})`),
+	},
+	{
+		description: "exfiltration command",
+		pattern:     regexp.MustCompile(`(?i)\b(?:curl|wget)\b[^\n]*(?:\$\{?[A-Z_]*(?:TOKEN|KEY|SECRET|PASSWORD)|\.env\b|id_rsa|\.aws/credentials)`),
+	},
+}
+
+// findInjections reports lines of an overlay matching a prompt-injection
+// heuristic, at most one finding per line.
+func findInjections(overlay, content string) []string {
+	var findings []string
+	for i, line := range markdown.SplitLines(content) {
+		for _, rule := range injectionRules {
+			if m := rule.pattern.FindString(line); m != "" {
+				findings = append(findings, fmt.Sprintf("overlay %s:%d: possible prompt injection (%s): %q", overlay, i+1, rule.description, m))
+				break
+			}
+		}
+	}
+	return findings
+}
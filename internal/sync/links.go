@@ -0,0 +1,95 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ailign/cli/internal/markdown"
+)
+
+// relocateLink rewrites a link path relative to fromDir so it resolves to
+// the same file from toDir. Both directories are slash-separated and
+// relative to the repository root.
+func relocateLink(p, fromDir, toDir string) string {
+	resolved := path.Join(fromDir, p)
+	rel, err := filepath.Rel(filepath.FromSlash(toDir), filepath.FromSlash(resolved))
+	if err != nil {
+		return p
+	}
+	rel = filepath.ToSlash(rel)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(rel, "/") {
+		rel += "/"
+	}
+	return rel
+}
+
+// rewriteLinks rewrites every relative link destination in content from
+// being relative to fromDir to being relative to toDir.
+func rewriteLinks(content, fromDir, toDir string) string {
+	fromDir, toDir = path.Clean(fromDir), path.Clean(toDir)
+	if fromDir == toDir {
+		return content
+	}
+	return replaceLinks(content, markdown.RelativeLinks(content), func(ref markdown.Link) string {
+		p, suffix := markdown.SplitLinkDest(ref.Dest)
+		if p == "" {
+			return ref.Dest
+		}
+		return relocateLink(p, fromDir, toDir) + suffix
+	})
+}
+
+// replaceLinks substitutes each ref's destination with the value returned
+// by fn, preserving angle brackets.
+func replaceLinks(content string, refs []markdown.Link, fn func(markdown.Link) string) string {
+	if len(refs) == 0 {
+		return content
+	}
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		b.WriteString(content[last:ref.Start])
+		dest := fn(ref)
+		if ref.Angled {
+			dest = "<" + dest + ">"
+		}
+		b.WriteString(dest)
+		last = ref.End
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// rewriteOverlayLinks rewrites relative links in an overlay so they resolve
+// from outputDir instead of the overlay's own directory, and returns a
+// warning for each link whose target does not exist under baseDir.
+func rewriteOverlayLinks(baseDir, overlay, content, outputDir string) (string, []string) {
+	var warnings []string
+	overlayDir := path.Dir(filepath.ToSlash(filepath.Clean(overlay)))
+	outputDir = path.Clean(outputDir)
+
+	rewritten := replaceLinks(content, markdown.RelativeLinks(content), func(ref markdown.Link) string {
+		p, suffix := markdown.SplitLinkDest(ref.Dest)
+		if p == "" {
+			return ref.Dest
+		}
+		decoded, err := url.PathUnescape(p)
+		if err != nil {
+			decoded = p
+		}
+		resolved := path.Join(overlayDir, decoded)
+		if _, err := os.Stat(filepath.Join(baseDir, filepath.FromSlash(resolved))); errors.Is(err, os.ErrNotExist) {
+			warnings = append(warnings, fmt.Sprintf("overlay %s:%d: link target not found: %s", overlay, ref.Line, resolved))
+		}
+		if overlayDir == outputDir {
+			return ref.Dest
+		}
+		return relocateLink(p, overlayDir, outputDir) + suffix
+	})
+	return rewritten, warnings
+}
@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// Link rewriting tests
+// ---------------------------------------------------------------------------
+
+func TestRewriteLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		from, to string
+		want     string
+	}{
+		{"inline link", "See [docs](docs/a.md).", ".", ".ailign", "See [docs](../docs/a.md)."},
+		{"image", "![logo](img/logo.png)", ".", ".github", "![logo](../img/logo.png)"},
+		{"link with title", `[a](a.md "Title")`, ".ailign", ".", `[a](.ailign/a.md "Title")`},
+		{"fragment and query kept", "[a](docs/a.md#setup)", ".", ".ailign", "[a](../docs/a.md#setup)"},
+		{"angle brackets", "[a](<my docs/a.md>)", ".", ".ailign", "[a](<../my docs/a.md>)"},
+		{"reference definition", "[ref]: docs/a.md", ".", ".claude", "[ref]: ../docs/a.md"},
+		{"same directory unchanged", "[a](a.md)", ".ailign", ".ailign", "[a](a.md)"},
+		{"absolute url untouched", "[a](https://example.com/a.md)", ".", ".ailign", "[a](https://example.com/a.md)"},
+		{"mailto untouched", "[a](mailto:dev@example.com)", ".", ".ailign", "[a](mailto:dev@example.com)"},
+		{"anchor untouched", "[a](#section)", ".", ".ailign", "[a](#section)"},
+		{"root path untouched", "[a](/docs/a.md)", ".", ".ailign", "[a](/docs/a.md)"},
+		{"code span untouched", "Use `[a](b.md)` syntax", ".", ".ailign", "Use `[a](b.md)` syntax"},
+		{"fenced code untouched", "```\n[a](b.md)\n```\n", ".", ".ailign", "```\n[a](b.md)\n```\n"},
+		{"trailing slash kept", "[dir](docs/)", ".", ".ailign", "[dir](../docs/)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rewriteLinks(tt.content, tt.from, tt.to))
+		})
+	}
+}
+
+func TestComposeOverlays_RewritesLinksToOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "docs", "testing.md"), "# Testing\n")
+	writeFile(t, filepath.Join(dir, ".ai-instructions", "base.md"), "See [testing](../docs/testing.md).\n")
+
+	result, err := ComposeOverlays(dir, []string{".ai-instructions/base.md"}, ComposeOptions{OutputDir: ".ailign"})
+	require.NoError(t, err)
+	assert.Contains(t, string(result.Content), "[testing](../docs/testing.md)")
+	assert.Empty(t, result.Warnings)
+
+	result, err = ComposeOverlays(dir, []string{".ai-instructions/base.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, string(result.Content), "[testing](docs/testing.md)")
+}
+
+func TestComposeOverlays_MissingLinkTargetWarning(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".ai-instructions", "base.md"), "# Base\n\nSee [x](missing.md) and [y](https://example.com).\n")
+
+	result, err := ComposeOverlays(dir, []string{".ai-instructions/base.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "overlay .ai-instructions/base.md:3: link target not found: .ai-instructions/missing.md", result.Warnings[0])
+}
+
+func TestRenderTarget_RewritesPerTarget(t *testing.T) {
+	composed := &ComposeResult{Body: []byte("[guide](../docs/guide.md)\n"), Sources: []string{"base.md"}}
+	opts := RenderOptions{HubPath: ".ailign/instructions.md", OmitHeader: true}
+
+	assert.Equal(t, "[guide](docs/guide.md)\n", string(RenderTarget(composed, target.Cursor{}, opts)))
+	assert.Equal(t, "[guide](../docs/guide.md)\n", string(RenderTarget(composed, target.Copilot{}, opts)))
+	assert.False(t, linksBreakViaSymlink(composed, ".ailign/instructions.md", target.Claude{}))
+	assert.True(t, linksBreakViaSymlink(composed, ".ailign/instructions.md", target.Windsurf{}))
+}
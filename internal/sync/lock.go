@@ -0,0 +1,137 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LockPath is the advisory lock file, relative to the repository root,
+// that sync holds while it runs so concurrent runs (git hooks, editor
+// plugins, watch mode) don't interleave their writes.
+const LockPath = ".ailign/sync.lock"
+
+// LocalIgnorePath is the .gitignore, relative to the repository root,
+// that keeps the files sync writes for its own use (the lock and
+// StatePath) out of git whatever commit_outputs says. It lists itself, so it doesn't show up in git
+// status either.
+const LocalIgnorePath = ".ailign/.gitignore"
+
+// localIgnoreHeader starts a LocalIgnorePath file written by sync; one
+// without it belongs to the user and is left alone.
+const localIgnoreHeader = `# Written by "ailign sync": files it keeps for itself, never committed.`
+
+// localIgnore is the content of LocalIgnorePath.
+const localIgnore = localIgnoreHeader + "\n/.gitignore\n/state.json\n/sync.lock\n"
+
+// DefaultLockTimeout is how long the CLI waits for a concurrent sync to
+// finish before giving up.
+const DefaultLockTimeout = 10 * time.Second
+
+// lockPollInterval is how often a waiting run retries the lock.
+const lockPollInterval = 50 * time.Millisecond
+
+// LockedError reports that another process held the sync lock for the
+// whole wait timeout.
+type LockedError struct {
+	Path    string        // lock file, relative to the repository root
+	PID     int           // holder's process ID; 0 if it could not be read
+	Timeout time.Duration // how long we waited
+}
+
+func (e *LockedError) Error() string {
+	holder := "another ailign process"
+	if e.PID > 0 {
+		holder = fmt.Sprintf("another ailign process (PID %d)", e.PID)
+	}
+	return fmt.Sprintf("%s is syncing this repository; gave up waiting for the lock on %s after %s",
+		holder, e.Path, e.Timeout)
+}
+
+// Lock is a held sync lock. Release it when the run is done.
+type Lock struct {
+	file *os.File
+}
+
+// AcquireLock takes the sync lock for the repository at baseDir,
+// waiting up to timeout for a concurrent run to release it. A zero
+// timeout fails at once if the lock is held. The holder's PID is
+// written into the lock file so a waiting run can name it, and the lock
+// file and the state manifest are listed in LocalIgnorePath so they
+// never show up in git status.
+func AcquireLock(baseDir string, timeout time.Duration) (*Lock, error) {
+	lockPath := filepath.Join(baseDir, filepath.FromSlash(LockPath))
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("creating lock directory: %w", err)
+	}
+	if err := writeLocalIgnore(baseDir); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := tryLock(lockPath)
+		if err != nil {
+			return nil, fmt.Errorf("locking %s: %w", LockPath, err)
+		}
+		if f != nil {
+			if err := writeLockPID(f); err != nil {
+				_ = unlock(f)
+				return nil, fmt.Errorf("locking %s: %w", LockPath, err)
+			}
+			return &Lock{file: f}, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, &LockedError{Path: LockPath, PID: readLockPID(lockPath), Timeout: timeout}
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Release gives up the lock.
+func (l *Lock) Release() error {
+	return unlock(l.file)
+}
+
+// writeLocalIgnore brings LocalIgnorePath up to date, unless the user
+// wrote a file of their own there.
+func writeLocalIgnore(baseDir string) error {
+	ignorePath := filepath.Join(baseDir, filepath.FromSlash(LocalIgnorePath))
+	data, err := os.ReadFile(ignorePath)
+	switch {
+	case err == nil && (string(data) == localIgnore || !strings.HasPrefix(string(data), localIgnoreHeader)):
+		return nil
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("reading %s: %w", LocalIgnorePath, err)
+	}
+	if err := writeFileAtomic(ignorePath, []byte(localIgnore)); err != nil {
+		return fmt.Errorf("writing %s: %w", LocalIgnorePath, err)
+	}
+	return nil
+}
+
+func writeLockPID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
+
+// readLockPID returns the PID recorded in the lock file, or 0 if there
+// is none (the holder may not have written it yet).
+func readLockPID(lockPath string) int {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,32 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package sync
+
+import (
+	"errors"
+	"os"
+)
+
+// tryLock creates the lock file exclusively. It returns a nil file if
+// the file already exists, i.e. another process holds the lock. Without
+// flock, a run that crashes leaves the file behind; it must then be
+// removed by hand.
+func tryLock(lockPath string) (*os.File, error) {
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// unlock closes and removes the lock file.
+func unlock(f *os.File) error {
+	err := f.Close()
+	if rerr := os.Remove(f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLock_RecordsHolder(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireLock(dir, 0)
+	require.NoError(t, err)
+	defer func() { _ = lock.Release() }()
+
+	assert.Equal(t, os.Getpid(), readLockPID(filepath.Join(dir, ".ailign", "sync.lock")))
+}
+
+func TestAcquireLock_WritesLocalIgnore(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // "" for no file
+		want     string
+	}{
+		{"missing", "", localIgnore},
+		{"outdated", localIgnoreHeader + "\n/sync.lock\n", localIgnore},
+		{"user's own", "*.tmp\n", "*.tmp\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			ignorePath := filepath.Join(dir, filepath.FromSlash(LocalIgnorePath))
+			if tt.existing != "" {
+				writeFile(t, ignorePath, tt.existing)
+			}
+
+			lock, err := AcquireLock(dir, 0)
+			require.NoError(t, err)
+			require.NoError(t, lock.Release())
+
+			data, err := os.ReadFile(ignorePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestAcquireLock_HeldFailsAfterTimeout(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireLock(dir, 0)
+	require.NoError(t, err)
+	defer func() { _ = lock.Release() }()
+
+	start := time.Now()
+	_, err = AcquireLock(dir, 200*time.Millisecond)
+	require.Error(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, os.Getpid(), locked.PID)
+	assert.Contains(t, err.Error(), "PID")
+	assert.Contains(t, err.Error(), LockPath)
+}
+
+func TestAcquireLock_WaitsForRelease(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireLock(dir, 0)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = lock.Release()
+	}()
+
+	second, err := AcquireLock(dir, 5*time.Second)
+	require.NoError(t, err)
+	assert.NoError(t, second.Release())
+}
+
+func TestAcquireLock_ReusableAfterRelease(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		lock, err := AcquireLock(dir, 0)
+		require.NoError(t, err)
+		require.NoError(t, lock.Release())
+	}
+}
+
+func TestSync_HeldLock(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "Content\n")
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md"}}
+
+	lock, err := AcquireLock(dir, 0)
+	require.NoError(t, err)
+	defer func() { _ = lock.Release() }()
+
+	_, err = Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{})
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked), "got %v", err)
+	_, statErr := os.Stat(filepath.Join(dir, ".ailign", "instructions.md"))
+	assert.True(t, os.IsNotExist(statErr), "nothing is written while another run holds the lock")
+
+	// A dry run only reads and doesn't wait
+	result, err := Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "written", result.HubStatus)
+}
@@ -0,0 +1,324 @@
+package sync
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ailign/cli/internal/markdown"
+)
+
+// Section directive actions. A directive is an HTML comment on the line
+// directly above a heading, e.g. "<!-- ailign:replace -->".
+const (
+	actionReplace = "replace"
+	actionAppend  = "append"
+	actionDelete  = "delete"
+)
+
+var directivePattern = regexp.MustCompile(`^[ \t]*<!--[ \t]*ailign:[ \t]*([A-Za-z-]*)[ \t]*-->[ \t]*$`)
+
+// block is a contiguous run of Markdown text. Heading blocks start at an
+// ATX heading and run until the next heading of any level; level-0 blocks
+// are headless text (the preamble before the first heading, or content
+// appended into a section by a later overlay).
+type block struct {
+	Level  int    // heading level 1-6; 0 for headless text
+	Title  string // normalized heading text; empty for headless text
+	Text   string // raw text, including the heading line
+	Source string // overlay the text came from
+	Line   int    // 1-based line of the block's first line in Source
+	Action string // section directive attached to the heading, if any
+}
+
+// document is a parsed overlay: an ordered list of blocks whose texts
+// concatenate back to the original content (minus directive lines).
+type document struct {
+	Source     string
+	Blocks     []*block
+	directives int
+	duplicate  bool // identical to an earlier overlay; excluded from finer checks
+}
+
+// heading returns the block's heading in Markdown form, e.g. "## Testing".
+func (b *block) heading() string {
+	return strings.Repeat("#", b.Level) + " " + b.Title
+}
+
+// body returns the block text without its heading line.
+func (b *block) body() string {
+	if b.Level == 0 {
+		return b.Text
+	}
+	if i := strings.IndexByte(b.Text, '\n'); i >= 0 {
+		return b.Text[i+1:]
+	}
+	return ""
+}
+
+// parseMarkdown splits overlay content into heading-delimited blocks.
+// Headings inside fenced code blocks are ignored. Section directives are
+// consumed and attached to the heading that follows them.
+func parseMarkdown(source, content string) (*document, error) {
+	doc := &document{Source: source}
+	current := &block{Source: source, Line: 1}
+
+	var fence string
+	var pending string
+	pendingLine := 0
+
+	lines := markdown.SplitLines(content)
+	for i, line := range lines {
+		lineNo := i + 1
+		trimmed := strings.TrimRight(line, "\r\n")
+
+		if fence != "" {
+			if markdown.IsFenceClose(trimmed, fence) {
+				fence = ""
+			}
+			current.Text += line
+			continue
+		}
+
+		if f := markdown.FenceOpener(trimmed); f != "" {
+			if pending != "" {
+				return nil, fmt.Errorf("overlay %s:%d: ailign:%s directive must be followed by a heading", source, pendingLine, pending)
+			}
+			fence = f
+			current.Text += line
+			continue
+		}
+
+		if m := directivePattern.FindStringSubmatch(trimmed); m != nil {
+			action := strings.ToLower(m[1])
+			switch action {
+			case actionReplace, actionAppend, actionDelete:
+			default:
+				return nil, fmt.Errorf("overlay %s:%d: unknown ailign directive %q (expected replace, append or delete)", source, lineNo, m[1])
+			}
+			if pending != "" {
+				return nil, fmt.Errorf("overlay %s:%d: ailign:%s directive must be followed by a heading", source, pendingLine, pending)
+			}
+			pending = action
+			pendingLine = lineNo
+			doc.directives++
+			continue
+		}
+
+		if level, title, ok := markdown.ParseHeading(trimmed); ok {
+			if current.Text != "" {
+				doc.Blocks = append(doc.Blocks, current)
+			}
+			current = &block{
+				Level:  level,
+				Title:  title,
+				Text:   line,
+				Source: source,
+				Line:   lineNo,
+				Action: pending,
+			}
+			pending = ""
+			continue
+		}
+
+		if pending != "" && strings.TrimSpace(trimmed) != "" {
+			return nil, fmt.Errorf("overlay %s:%d: ailign:%s directive must be followed by a heading", source, pendingLine, pending)
+		}
+		if current.Text == "" && current.Level == 0 {
+			current.Line = lineNo
+		}
+		current.Text += line
+	}
+
+	if pending != "" {
+		return nil, fmt.Errorf("overlay %s:%d: ailign:%s directive must be followed by a heading", source, pendingLine, pending)
+	}
+	if current.Text != "" {
+		doc.Blocks = append(doc.Blocks, current)
+	}
+	return doc, nil
+}
+
+// sectionEnd returns the index one past the last block belonging to the
+// section that starts at blocks[i], including its subsections.
+func sectionEnd(blocks []*block, i int) int {
+	level := blocks[i].Level
+	if level == 0 {
+		return i + 1
+	}
+	for j := i + 1; j < len(blocks); j++ {
+		if blocks[j].Level > 0 && blocks[j].Level <= level {
+			return j
+		}
+	}
+	return len(blocks)
+}
+
+// applySectionDirectives applies replace/append/delete directives in
+// overlay order. Each directive targets the most recent section with the
+// same heading level and text (case-insensitive) in an earlier overlay.
+// Directives that match nothing are errors, so composition never
+// silently drops an override.
+func applySectionDirectives(docs []*document) ([]SectionOperation, error) {
+	var ops []SectionOperation
+	var errs []string
+
+	for di, doc := range docs {
+		for i := 0; i < len(doc.Blocks); {
+			b := doc.Blocks[i]
+			if b.Action == "" {
+				i++
+				continue
+			}
+
+			end := sectionEnd(doc.Blocks, i)
+			patch := doc.Blocks[i:end]
+			for _, nested := range patch[1:] {
+				if nested.Action != "" {
+					errs = append(errs, fmt.Sprintf("overlay %s:%d: ailign:%s directive is nested inside %q, which already has a directive",
+						doc.Source, nested.Line, nested.Action, b.heading()))
+				}
+			}
+
+			op := SectionOperation{
+				Overlay: doc.Source,
+				Line:    b.Line,
+				Action:  b.Action,
+				Heading: b.heading(),
+			}
+
+			target, ti := findSection(docs[:di], b)
+			if target == nil {
+				errs = append(errs, fmt.Sprintf("overlay %s:%d: cannot %s section %q: no earlier overlay defines it",
+					doc.Source, b.Line, b.Action, b.heading()))
+			} else {
+				op.TargetOverlay = target.Blocks[ti].Source
+				op.TargetLine = target.Blocks[ti].Line
+				applyDirective(target, ti, b.Action, patch)
+				ops = append(ops, op)
+			}
+
+			doc.Blocks = append(doc.Blocks[:i:i], doc.Blocks[end:]...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return ops, nil
+}
+
+// findSection returns the document and block index of the last section in
+// docs matching the heading of b.
+func findSection(docs []*document, b *block) (*document, int) {
+	for d := len(docs) - 1; d >= 0; d-- {
+		blocks := docs[d].Blocks
+		for i := len(blocks) - 1; i >= 0; i-- {
+			if blocks[i].Level == b.Level && strings.EqualFold(blocks[i].Title, b.Title) {
+				return docs[d], i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// applyDirective mutates target according to action, using patch (the
+// directive's heading block and its subsections) as the new content.
+func applyDirective(target *document, ti int, action string, patch []*block) {
+	end := sectionEnd(target.Blocks, ti)
+
+	var insert []*block
+	switch action {
+	case actionReplace:
+		for _, p := range patch {
+			c := *p
+			c.Action = ""
+			insert = append(insert, &c)
+		}
+		target.Blocks = splice(target.Blocks, ti, end, insert)
+	case actionAppend:
+		head := patch[0]
+		if body := head.body(); body != "" {
+			insert = append(insert, &block{Text: body, Source: head.Source, Line: head.Line + 1})
+		}
+		for _, p := range patch[1:] {
+			c := *p
+			insert = append(insert, &c)
+		}
+		target.Blocks = splice(target.Blocks, end, end, insert)
+	case actionDelete:
+		target.Blocks = splice(target.Blocks, ti, end, nil)
+	}
+}
+
+// splice returns blocks with blocks[from:to] replaced by insert.
+func splice(blocks []*block, from, to int, insert []*block) []*block {
+	out := make([]*block, 0, len(blocks)-(to-from)+len(insert))
+	out = append(out, blocks[:from]...)
+	out = append(out, insert...)
+	return append(out, blocks[to:]...)
+}
+
+// render concatenates the document's blocks, inserting a newline between
+// blocks when a preceding block does not end with one.
+func (d *document) render() string {
+	return joinSegments(d.segments())
+}
+
+// segments returns the document's text attributed to the overlays its
+// blocks came from. A newline inserted between blocks belongs to the
+// block before it.
+func (d *document) segments() []Segment {
+	var segs []Segment
+	for _, blk := range d.Blocks {
+		if n := len(segs); n > 0 && !strings.HasSuffix(segs[n-1].Text, "\n") {
+			segs[n-1].Text += "\n"
+		}
+		segs = appendSegment(segs, blk.Source, blk.Text)
+	}
+	return segs
+}
+
+// renderSegments joins rendered documents with a newline, which belongs
+// to the document that follows it. Overlays that consisted only of
+// section directives contribute nothing.
+func renderSegments(docs []*document) []Segment {
+	var segs []Segment
+	parts := 0
+	for _, d := range docs {
+		ds := d.segments()
+		if d.directives > 0 && strings.TrimSpace(joinSegments(ds)) == "" {
+			continue
+		}
+		if parts > 0 {
+			segs = appendSegment(segs, d.Source, "\n")
+		}
+		for _, seg := range ds {
+			segs = appendSegment(segs, seg.Overlay, seg.Text)
+		}
+		parts++
+	}
+	return segs
+}
+
+// appendSegment adds text to segs, extending the last segment when it
+// has the same source.
+func appendSegment(segs []Segment, source, text string) []Segment {
+	if text == "" {
+		return segs
+	}
+	if n := len(segs); n > 0 && segs[n-1].Overlay == source {
+		segs[n-1].Text += text
+		return segs
+	}
+	return append(segs, Segment{Overlay: source, Text: text})
+}
+
+// joinSegments concatenates segment texts.
+func joinSegments(segs []Segment) string {
+	var b strings.Builder
+	for _, seg := range segs {
+		b.WriteString(seg.Text)
+	}
+	return b.String()
+}
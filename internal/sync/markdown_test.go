@@ -0,0 +1,184 @@
+package sync
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// parseMarkdown tests
+// ---------------------------------------------------------------------------
+
+func TestParseMarkdown_SplitsOnHeadings(t *testing.T) {
+	doc, err := parseMarkdown("a.md", "Intro\n\n# Title\n\nText\n\n## Testing\n\nRun tests.\n")
+	require.NoError(t, err)
+	require.Len(t, doc.Blocks, 3)
+
+	assert.Equal(t, 0, doc.Blocks[0].Level)
+	assert.Equal(t, 1, doc.Blocks[1].Level)
+	assert.Equal(t, "Title", doc.Blocks[1].Title)
+	assert.Equal(t, 3, doc.Blocks[1].Line)
+	assert.Equal(t, 2, doc.Blocks[2].Level)
+	assert.Equal(t, "Testing", doc.Blocks[2].Title)
+	assert.Equal(t, 7, doc.Blocks[2].Line)
+}
+
+func TestParseMarkdown_RoundTrips(t *testing.T) {
+	content := "Intro\n# A\ntext\n## B\nmore"
+	doc, err := parseMarkdown("a.md", content)
+	require.NoError(t, err)
+	assert.Equal(t, content, doc.render())
+}
+
+func TestParseMarkdown_IgnoresHeadingsInFences(t *testing.T) {
+	doc, err := parseMarkdown("a.md", "# Real\n\n```sh\n# not a heading\n```\n")
+	require.NoError(t, err)
+	require.Len(t, doc.Blocks, 1)
+	assert.Contains(t, doc.Blocks[0].Text, "# not a heading")
+}
+
+func TestParseMarkdown_NormalizesHeadingText(t *testing.T) {
+	doc, err := parseMarkdown("a.md", "##   Code   Style  ##\n")
+	require.NoError(t, err)
+	require.Len(t, doc.Blocks, 1)
+	assert.Equal(t, "Code Style", doc.Blocks[0].Title)
+}
+
+func TestParseMarkdown_DirectiveAttachesToHeading(t *testing.T) {
+	doc, err := parseMarkdown("b.md", "<!-- ailign:replace -->\n## Testing\nNew.\n")
+	require.NoError(t, err)
+	require.Len(t, doc.Blocks, 1)
+	assert.Equal(t, actionReplace, doc.Blocks[0].Action)
+	assert.NotContains(t, doc.render(), "ailign:replace")
+}
+
+func TestParseMarkdown_DirectiveErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown action", "<!-- ailign:merge -->\n## A\n", "unknown ailign directive"},
+		{"not followed by heading", "<!-- ailign:delete -->\ntext\n", "must be followed by a heading"},
+		{"at end of file", "## A\n<!-- ailign:append -->\n", "must be followed by a heading"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMarkdown("b.md", tt.content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Contains(t, err.Error(), "b.md:")
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Section directive composition tests
+// ---------------------------------------------------------------------------
+
+func TestComposeOverlays_ReplaceSection(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\n## Testing\n\nUse mocks.\n\n### Unit\n\nOld unit.\n\n## Style\n\nBe terse.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:replace -->\n## Testing\n\nUse real databases.\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+
+	content := string(result.Content)
+	assert.Contains(t, content, "Use real databases.")
+	assert.NotContains(t, content, "Use mocks.")
+	assert.NotContains(t, content, "Old unit.", "subsections are replaced with their parent")
+	assert.Less(t, strings.Index(content, "Use real databases."), strings.Index(content, "## Style"),
+		"replacement keeps the original position")
+
+	require.Len(t, result.Operations, 1)
+	op := result.Operations[0]
+	assert.Equal(t, "repo.md", op.Overlay)
+	assert.Equal(t, "replace", op.Action)
+	assert.Equal(t, "## Testing", op.Heading)
+	assert.Equal(t, "base.md", op.TargetOverlay)
+	assert.Equal(t, 3, op.TargetLine)
+}
+
+func TestComposeOverlays_AppendSection(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "## Testing\n\nUse mocks.\n\n## Style\n\nBe terse.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:append -->\n## Testing\n\nAlso run e2e.\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+
+	content := string(result.Content)
+	mocks := strings.Index(content, "Use mocks.")
+	e2e := strings.Index(content, "Also run e2e.")
+	style := strings.Index(content, "## Style")
+	assert.True(t, mocks < e2e && e2e < style, "appended text goes at the end of the section")
+	assert.Equal(t, 1, strings.Count(content, "## Testing"))
+}
+
+func TestComposeOverlays_DeleteSection(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "## Testing\n\nUse mocks.\n\n## Style\n\nBe terse.\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:delete -->\n## testing\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+
+	content := string(result.Content)
+	assert.NotContains(t, content, "Use mocks.")
+	assert.Contains(t, content, "Be terse.")
+	assert.False(t, strings.HasSuffix(content, "\n\n"), "directive-only overlays add no trailing content")
+}
+
+func TestComposeOverlays_DirectiveWithoutTarget(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "## Style\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:replace -->\n## Testing\n")
+
+	_, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "repo.md:2")
+	assert.Contains(t, err.Error(), "no earlier overlay defines it")
+}
+
+func TestComposeOverlays_LevelMustMatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "### Testing\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:delete -->\n## Testing\n")
+
+	_, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.Error(t, err)
+}
+
+func TestComposeOverlays_Deterministic(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "## A\na\n## B\nb\n")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:append -->\n## A\na2\n<!-- ailign:replace -->\n## B\nb2\n")
+
+	first, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	second, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, first.Content, second.Content)
+	assert.Len(t, first.Operations, 2)
+}
+
+func TestComposeOverlays_SegmentsAttributeBody(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\n## Testing\n\nUse mocks.\n\n## Style\n\nBe terse.")
+	writeFile(t, filepath.Join(dir, "repo.md"), "<!-- ailign:replace -->\n## Testing\n\nUse real databases.\n")
+
+	result, err := ComposeOverlays(dir, []string{"base.md", "repo.md"}, ComposeOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, result.Segments, 3)
+	assert.Equal(t, "base.md", result.Segments[0].Overlay)
+	assert.Equal(t, "repo.md", result.Segments[1].Overlay)
+	assert.Equal(t, "## Testing\n\nUse real databases.\n", result.Segments[1].Text)
+	assert.Equal(t, "base.md", result.Segments[2].Overlay)
+	assert.Equal(t, string(result.Body), joinSegments(result.Segments))
+}
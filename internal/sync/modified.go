@@ -0,0 +1,233 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+)
+
+// ModifiedFile is a managed file whose content changed since sync last
+// wrote it, and that this run would overwrite.
+type ModifiedFile struct {
+	Path   string // slash-separated, relative to the repository root
+	Target string // empty for the hub
+	// Via lists the target symlinks that point at the hub; an edit made
+	// through any of them lands in the hub.
+	Via []string
+	// Overlays are the overlays the edited lines were composed from, in
+	// order. The change belongs in one of them.
+	Overlays []string
+	// InHeader is set when the edit touches the managed header, which
+	// is generated and can only be changed through hub.header.
+	InHeader bool
+}
+
+// Advice says where the change to the file should be made instead.
+func (f ModifiedFile) Advice() string {
+	var parts []string
+	if len(f.Overlays) > 0 {
+		parts = append(parts, "move the change into "+joinOr(f.Overlays))
+	}
+	if f.InHeader {
+		parts = append(parts, "change the managed header through hub.header in .ailign.yml")
+	}
+	if len(parts) == 0 {
+		return "move the change into an overlay"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ModifiedError reports managed files that were edited outside ailign.
+// Sync returns it rather than overwrite them, unless SyncOptions.Force
+// is set.
+type ModifiedError struct {
+	Files []ModifiedFile
+}
+
+func (e *ModifiedError) Error() string {
+	var b strings.Builder
+	n := len(e.Files)
+	if n == 1 {
+		b.WriteString("1 file managed by ailign was edited since the last sync and would be overwritten:\n")
+	} else {
+		fmt.Fprintf(&b, "%d files managed by ailign were edited since the last sync and would be overwritten:\n", n)
+	}
+	for _, f := range e.Files {
+		fmt.Fprintf(&b, "  %s", f.Path)
+		if len(f.Via) > 0 {
+			fmt.Fprintf(&b, " (edits to %s land here)", strings.Join(f.Via, ", "))
+		}
+		fmt.Fprintf(&b, ": %s\n", f.Advice())
+	}
+	b.WriteString(`Make the changes in the overlays and sync again, or run "ailign sync --force" to discard them.`)
+	return b.String()
+}
+
+// findModified returns the managed files this run would overwrite that
+// were edited since sync wrote them: their content differs from the
+// digest recorded in prev or, for files prev doesn't cover, from the
+// body digest in their own header stamp. Files that already hold what
+// the run would write are not conflicts. Copy-mode targets outside
+// selected are left alone by the run and not checked.
+func findModified(baseDir string, prev *State, composed *ComposeResult, cfg *config.Config, registry *target.Registry, selected map[string]bool) []ModifiedFile {
+	var modified []ModifiedFile
+	header, segs := HubSegments(composed)
+	if m, ok := checkModified(baseDir, cfg.HubPath(), prev.File(""), composed.Content, header, segs); ok {
+		if cfg.OutputMode() == config.ModeSymlink {
+			m.Via = hubSymlinks(baseDir, cfg, registry)
+		}
+		modified = append(modified, m)
+	}
+
+	for _, name := range cfg.Targets {
+		tgt, ok := registry.Get(name)
+		if !ok || !selected[name] {
+			continue
+		}
+		// A symlink's content is the hub's, checked above; checkModified
+		// only looks at targets something replaced with a regular file.
+		header, segs := TargetSegments(composed, cfg, tgt)
+		if m, ok := checkModified(baseDir, tgt.InstructionPath(), prev.File(name), TargetContent(composed, cfg, tgt), header, segs); ok {
+			m.Target = name
+			modified = append(modified, m)
+		}
+	}
+	return modified
+}
+
+// checkModified reports whether the regular file at relPath was edited
+// and differs from want, the content the run would write. recorded is
+// its manifest entry, if any.
+func checkModified(baseDir, relPath string, recorded *StateFile, want []byte, header string, segs []Segment) (ModifiedFile, bool) {
+	relPath = filepath.ToSlash(relPath)
+	p := filepath.Join(baseDir, filepath.FromSlash(relPath))
+	info, err := os.Lstat(p)
+	if err != nil || !info.Mode().IsRegular() {
+		return ModifiedFile{}, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil || string(data) == string(want) {
+		return ModifiedFile{}, false
+	}
+
+	var edited bool
+	if recorded != nil && recorded.Path == relPath {
+		edited = contentDigest(data) != recorded.Digest
+	} else {
+		edited = editedSinceStamped(string(data))
+	}
+	if !edited {
+		return ModifiedFile{}, false
+	}
+	m := ModifiedFile{Path: relPath}
+	m.Overlays, m.InHeader = editedSources(header, segs, string(want), string(data))
+	return m, true
+}
+
+// hubSymlinks lists the configured targets that are symlinks to the hub.
+func hubSymlinks(baseDir string, cfg *config.Config, registry *target.Registry) []string {
+	hubPath := filepath.Join(baseDir, filepath.FromSlash(cfg.HubPath()))
+	var links []string
+	for _, name := range cfg.Targets {
+		tgt, ok := registry.Get(name)
+		if !ok {
+			continue
+		}
+		if status, err := CheckSymlinkStatus(filepath.Join(baseDir, tgt.InstructionPath()), hubPath); err == nil && status == "exists" {
+			links = append(links, filepath.ToSlash(tgt.InstructionPath()))
+		}
+	}
+	return links
+}
+
+// editedSources finds the lines where got differs from want and returns
+// the overlays those lines of want were composed from, and whether any
+// of them belong to the header. want must be header followed by the
+// segments' text. A pure insertion is attributed to the line above it.
+// Edits made on top of overlays that changed since the last sync are
+// attributed on a best-effort basis; header stamp lines, which change
+// with every such edit, are not counted as edited.
+func editedSources(header string, segs []Segment, want, got string) ([]string, bool) {
+	var b strings.Builder
+	b.WriteString(header)
+	for _, s := range segs {
+		b.WriteString(s.Text)
+	}
+	if b.String() != want || want == "" {
+		return nil, false
+	}
+
+	wantLines := strings.SplitAfter(want, "\n")
+	gotLines := strings.SplitAfter(got, "\n")
+	start := 0
+	for start < len(wantLines) && start < len(gotLines) && sameLine(wantLines[start], gotLines[start]) {
+		start++
+	}
+	end := len(wantLines)
+	for g := len(gotLines); end > start && g > start && sameLine(wantLines[end-1], gotLines[g-1]); g-- {
+		end--
+	}
+	if start == end {
+		start = max(start-1, 0)
+		end = start + 1
+	}
+
+	// owner returns the overlay that the byte at offset came from, or ""
+	// for the header.
+	owner := func(offset int) string {
+		pos := len(header)
+		if offset < pos || len(segs) == 0 {
+			return ""
+		}
+		for _, s := range segs {
+			pos += len(s.Text)
+			if offset < pos {
+				return s.Overlay
+			}
+		}
+		return segs[len(segs)-1].Overlay
+	}
+
+	var overlays []string
+	inHeader := false
+	offset := 0
+	for i, line := range wantLines {
+		if i >= start && i < end && line != "" {
+			switch o := owner(offset); {
+			case o == "":
+				inHeader = true
+			case !slices.Contains(overlays, o):
+				overlays = append(overlays, o)
+			}
+		}
+		offset += len(line)
+	}
+	return overlays, inHeader
+}
+
+// sameLine reports whether two lines are equal, treating the header
+// stamp lines with the same key as equal whatever their values.
+func sameLine(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, key := range []string{"Generator: ", "Sources-Digest: ", "Body-Digest: "} {
+		if strings.HasPrefix(strings.TrimLeft(a, " #"), key) && strings.HasPrefix(strings.TrimLeft(b, " #"), key) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinOr joins names as "a", "a or b", or "a, b or c".
+func joinOr(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
@@ -0,0 +1,186 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync_RefusesToOverwriteHubEditedThroughSymlink(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse Go.\n")
+	writeFile(t, filepath.Join(dir, "team.md"), "# Team\n\nReview everything.\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md", "team.md"}}
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	// Appending through the symlink edits the hub
+	cursor := filepath.Join(dir, ".cursorrules")
+	data, err := os.ReadFile(cursor)
+	require.NoError(t, err)
+	edited := string(data) + "Also review docs.\n"
+	require.NoError(t, os.WriteFile(cursor, []byte(edited), 0644))
+
+	for _, dryRun := range []bool{true, false} {
+		_, err = Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{DryRun: dryRun})
+		var modErr *ModifiedError
+		require.True(t, errors.As(err, &modErr), "dry run %v: got %v", dryRun, err)
+		require.Len(t, modErr.Files, 1)
+		f := modErr.Files[0]
+		assert.Equal(t, ".ailign/instructions.md", f.Path)
+		assert.Empty(t, f.Target)
+		assert.Equal(t, []string{".claude/instructions.md", ".cursorrules"}, f.Via)
+		assert.Equal(t, []string{"team.md"}, f.Overlays)
+		assert.Contains(t, err.Error(), "move the change into team.md")
+		assert.Contains(t, err.Error(), "(edits to .claude/instructions.md, .cursorrules land here)")
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, ".ailign", "instructions.md"))
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(data), "the edit must survive a refused sync")
+
+	result := syncForState(t, dir, cfg, SyncOptions{Force: true})
+	assert.Equal(t, "written", result.HubStatus)
+	data, err = os.ReadFile(filepath.Join(dir, ".ailign", "instructions.md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Also review docs.")
+}
+
+func TestSync_RefusesToOverwriteEditedCopy(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse Go.\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	cursor := filepath.Join(dir, ".cursorrules")
+	data, err := os.ReadFile(cursor)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cursor, []byte(string(data)+"Use tabs.\n"), 0644))
+
+	// A run that leaves the edited target alone goes ahead
+	syncForState(t, dir, cfg, SyncOptions{SkipTargets: []string{"cursor"}})
+
+	_, err = Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{})
+	var modErr *ModifiedError
+	require.True(t, errors.As(err, &modErr), "got %v", err)
+	require.Len(t, modErr.Files, 1)
+	assert.Equal(t, ".cursorrules", modErr.Files[0].Path)
+	assert.Equal(t, "cursor", modErr.Files[0].Target)
+	assert.Empty(t, modErr.Files[0].Via)
+	assert.Equal(t, []string{"base.md"}, modErr.Files[0].Overlays)
+}
+
+func TestSync_EditsMatchingNewContentAreNotConflicts(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"cursor"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	// The same edit made in the overlay and the copy
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse tabs.\n")
+	composed, err := Compose(dir, cfg, "")
+	require.NoError(t, err)
+	tgt, _ := target.NewDefaultRegistry().Get("cursor")
+	writeFile(t, filepath.Join(dir, ".cursorrules"), string(TargetContent(composed, cfg, tgt)))
+
+	syncForState(t, dir, cfg, SyncOptions{})
+}
+
+func TestSync_NoManifestNoConflict(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	writeFile(t, filepath.Join(dir, ".ailign", "instructions.md"), "hand written\n")
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md"}}
+
+	result := syncForState(t, dir, cfg, SyncOptions{})
+	assert.Equal(t, "written", result.HubStatus)
+}
+
+func TestEditedSources(t *testing.T) {
+	header := "<!-- header -->\n\n"
+	segs := []Segment{
+		{Overlay: "base.md", Text: "# Base\n\nUse Go.\n"},
+		{Overlay: "team.md", Text: "\n# Team\n\nReview.\n"},
+	}
+	want := header + "# Base\n\nUse Go.\n\n# Team\n\nReview.\n"
+
+	tests := []struct {
+		name         string
+		got          string
+		wantOverlays []string
+		wantHeader   bool
+	}{
+		{"changed line", header + "# Base\n\nUse Rust.\n\n# Team\n\nReview.\n", []string{"base.md"}, false},
+		{"appended line", want + "More.\n", []string{"team.md"}, false},
+		{"inserted line", header + "# Base\n\nUse Go.\nAnd tests.\n\n# Team\n\nReview.\n", []string{"base.md"}, false},
+		{"spans overlays", header + "# Base\n\nchanged\n\nchanged\n\nReview.\n", []string{"base.md", "team.md"}, false},
+		{"header", "<!-- edited -->\n\n# Base\n\nUse Go.\n\n# Team\n\nReview.\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlays, inHeader := editedSources(header, segs, want, tt.got)
+			assert.Equal(t, tt.wantOverlays, overlays)
+			assert.Equal(t, tt.wantHeader, inHeader)
+		})
+	}
+}
+
+func TestModifiedFile_Advice(t *testing.T) {
+	assert.Equal(t, "move the change into a.md, b.md or c.md", ModifiedFile{Overlays: []string{"a.md", "b.md", "c.md"}}.Advice())
+	assert.Equal(t, "move the change into a.md; change the managed header through hub.header in .ailign.yml",
+		ModifiedFile{Overlays: []string{"a.md"}, InHeader: true}.Advice())
+	assert.Equal(t, "move the change into an overlay", ModifiedFile{}.Advice())
+}
+
+func TestSync_DetectsEditsFromHeaderStampWithoutManifest(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse Go.\n")
+	cfg := &config.Config{Targets: []string{"cursor"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+	syncForState(t, dir, cfg, SyncOptions{Version: "1.2.3"})
+	require.NoError(t, os.Remove(filepath.Join(dir, filepath.FromSlash(StatePath))))
+
+	// Overlays may change freely while the outputs are untouched
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nUse Go 1.24.\n")
+	syncForState(t, dir, cfg, SyncOptions{Version: "1.2.3", DryRun: true})
+
+	cursor := filepath.Join(dir, ".cursorrules")
+	data, err := os.ReadFile(cursor)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Generator: ailign 1.2.3\n")
+	require.NoError(t, os.WriteFile(cursor, []byte(string(data)+"Use tabs.\n"), 0644))
+
+	_, err = Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{Version: "1.2.3", DryRun: true})
+	var modErr *ModifiedError
+	require.True(t, errors.As(err, &modErr), "got %v", err)
+	require.Len(t, modErr.Files, 1)
+	assert.Equal(t, ".cursorrules", modErr.Files[0].Path)
+	assert.Equal(t, []string{"base.md"}, modErr.Files[0].Overlays)
+	assert.False(t, modErr.Files[0].InHeader, "stamp lines that changed with the overlays are not edits")
+}
+
+func TestSync_CRLFOverlayChangeIsNotAnEditWithoutManifest(t *testing.T) {
+	skipOnWindows(t)
+	for _, mode := range []string{config.ModeSymlink, config.ModeCopy} {
+		t.Run(mode, func(t *testing.T) {
+			dir := resolveDir(t)
+			writeFile(t, filepath.Join(dir, "base.md"), "# Base\r\n\r\nUse Go.\r\n")
+			cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}, Mode: mode}
+			syncForState(t, dir, cfg, SyncOptions{})
+			require.NoError(t, os.Remove(filepath.Join(dir, filepath.FromSlash(StatePath))))
+
+			writeFile(t, filepath.Join(dir, "base.md"), "# Base\r\n\r\nUse Go 1.24.\r\n")
+			syncForState(t, dir, cfg, SyncOptions{})
+		})
+	}
+}
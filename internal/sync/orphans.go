@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+)
+
+// findOrphans lists outputs sync created for targets that are no longer
+// configured: files recorded in the state manifest, and symlinks to the
+// hub at a known target's path, which sync created before it kept a
+// manifest. Paths in claimed belong to the current run and are never
+// orphans.
+func findOrphans(baseDir string, prev *State, cfg *config.Config, registry *target.Registry, claimed map[string]bool) []StateFile {
+	configured := make(map[string]bool, len(cfg.Targets))
+	for _, name := range cfg.Targets {
+		configured[name] = true
+	}
+
+	seen := make(map[string]bool)
+	var orphans []StateFile
+	if prev != nil {
+		for _, f := range prev.Files {
+			if f.Target == "" || configured[f.Target] || claimed[f.Path] || seen[f.Path] {
+				continue
+			}
+			seen[f.Path] = true
+			orphans = append(orphans, f)
+		}
+	}
+
+	hubPath := filepath.Join(baseDir, filepath.FromSlash(cfg.HubPath()))
+	for _, name := range registry.KnownTargets() {
+		tgt, _ := registry.Get(name)
+		relPath := filepath.ToSlash(tgt.InstructionPath())
+		if configured[name] || claimed[relPath] || seen[relPath] {
+			continue
+		}
+		status, err := CheckSymlinkStatus(filepath.Join(baseDir, tgt.InstructionPath()), hubPath)
+		if err != nil || status != "exists" {
+			continue
+		}
+		f, err := describeFile(baseDir, relPath, name, StateModeSymlink)
+		if err != nil {
+			continue
+		}
+		seen[relPath] = true
+		orphans = append(orphans, f)
+	}
+	return orphans
+}
+
+// removeOrphan deletes an orphaned output, and the directories sync
+// created for it if they are left empty, if it is still exactly as sync
+// left it. It returns the link result to report, or a warning when a
+// changed file is kept. A file that is already gone yields neither.
+func removeOrphan(baseDir string, f StateFile, dryRun bool) (*LinkResult, string) {
+	p := filepath.Join(baseDir, filepath.FromSlash(f.Path))
+	if !f.Intact(baseDir) {
+		if _, err := os.Lstat(p); errors.Is(err, os.ErrNotExist) {
+			return nil, ""
+		}
+		return nil, fmt.Sprintf("target %s is no longer configured, but %s was changed since ailign wrote it; left it in place",
+			f.Target, f.Path)
+	}
+
+	link := &LinkResult{Target: f.Target, LinkPath: filepath.FromSlash(f.Path), Mode: f.Mode, Status: "removed"}
+	if dryRun {
+		return link, ""
+	}
+	if err := os.Remove(p); err != nil {
+		link.Status = "error"
+		link.Error = fmt.Sprintf("removing orphaned output: %v", err)
+		return link, ""
+	}
+	removeCreatedDirs(baseDir, f.Dirs)
+	return link, ""
+}
+
+// missingDirs returns the directories between baseDir and the file at p
+// that don't exist yet, deepest first, relative to baseDir and
+// slash-separated: the ones writing p creates.
+func missingDirs(baseDir, p string) []string {
+	var dirs []string
+	for dir := filepath.Dir(p); dir != baseDir && len(dir) > len(baseDir); dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(dir); err == nil {
+			break
+		}
+		rel, err := filepath.Rel(baseDir, dir)
+		if err != nil {
+			break
+		}
+		dirs = append(dirs, filepath.ToSlash(rel))
+	}
+	return dirs
+}
+
+// removeCreatedDirs removes the directories sync created, deepest first,
+// for as long as they are empty.
+func removeCreatedDirs(baseDir string, dirs []string) {
+	for _, dir := range dirs {
+		if err := os.Remove(filepath.Join(baseDir, filepath.FromSlash(dir))); err != nil {
+			return // not empty: the user keeps files there
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func linkStatus(result *SyncResult, name string) string {
+	for _, l := range result.Links {
+		if l.Target == name {
+			return l.Status
+		}
+	}
+	return ""
+}
+
+func TestSync_RemovesDroppedTargets(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor", "copilot", "windsurf"}, LocalOverlays: []string{"base.md"}}
+	syncForState(t, dir, cfg, SyncOptions{})
+	writeFile(t, filepath.Join(dir, ".github", "workflows", "ci.yml"), "on: push\n")
+
+	// windsurf's output was replaced by hand; it must survive
+	require.NoError(t, os.Remove(filepath.Join(dir, ".windsurfrules")))
+	writeFile(t, filepath.Join(dir, ".windsurfrules"), "my own rules\n")
+
+	cfg.Targets = []string{"claude"}
+	preview := syncForState(t, dir, cfg, SyncOptions{DryRun: true})
+	assert.Equal(t, "removed", linkStatus(preview, "cursor"))
+	_, err := os.Lstat(filepath.Join(dir, ".cursorrules"))
+	require.NoError(t, err, "dry run removes nothing")
+
+	result := syncForState(t, dir, cfg, SyncOptions{})
+	assert.Equal(t, "removed", linkStatus(result, "cursor"))
+	assert.Equal(t, "removed", linkStatus(result, "copilot"))
+	assert.Empty(t, linkStatus(result, "windsurf"))
+	assert.Contains(t, result.Warnings, "target windsurf is no longer configured, but .windsurfrules was changed since ailign wrote it; left it in place")
+
+	_, err = os.Lstat(filepath.Join(dir, ".cursorrules"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+	assert.NoError(t, err, "directories with other files stay")
+	content, err := os.ReadFile(filepath.Join(dir, ".windsurfrules"))
+	require.NoError(t, err)
+	assert.Equal(t, "my own rules\n", string(content))
+
+	st, err := ReadState(dir)
+	require.NoError(t, err)
+	assert.Nil(t, st.File("cursor"))
+	assert.Nil(t, st.File("windsurf"))
+}
+
+func TestSync_RemovesEmptyTargetDirectory(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	cfg.Targets = []string{"cursor"}
+	result := syncForState(t, dir, cfg, SyncOptions{})
+
+	assert.Equal(t, "removed", linkStatus(result, "claude"))
+	_, err := os.Stat(filepath.Join(dir, ".claude"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSync_KeepsDirectoriesAilignDidNotCreate(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".github"), 0755))
+	cfg := &config.Config{Targets: []string{"claude", "copilot", "cursor"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	// A later run that rewrites the outputs keeps track of the directories
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nMore.\n")
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	cfg.Targets = []string{"cursor"}
+	result := syncForState(t, dir, cfg, SyncOptions{})
+	assert.Equal(t, "removed", linkStatus(result, "claude"))
+	assert.Equal(t, "removed", linkStatus(result, "copilot"))
+
+	_, err := os.Stat(filepath.Join(dir, ".claude"))
+	assert.True(t, os.IsNotExist(err), "sync created .claude")
+	info, err := os.Stat(filepath.Join(dir, ".github"))
+	require.NoError(t, err, "the user created .github")
+	assert.True(t, info.IsDir())
+}
+
+func TestSync_RemovesHubSymlinksWithoutState(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ailign"), 0755))
+	require.NoError(t, os.Symlink(".ailign/instructions.md", filepath.Join(dir, ".cursorrules")))
+	require.NoError(t, os.Symlink("elsewhere.md", filepath.Join(dir, ".windsurfrules")))
+
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md"}}
+	result := syncForState(t, dir, cfg, SyncOptions{})
+
+	assert.Equal(t, "removed", linkStatus(result, "cursor"))
+	assert.Empty(t, linkStatus(result, "windsurf"), "symlinks elsewhere are not ailign's")
+	_, err := os.Lstat(filepath.Join(dir, ".windsurfrules"))
+	assert.NoError(t, err)
+}
+
+func TestSync_KeepOrphans(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}}
+	syncForState(t, dir, cfg, SyncOptions{})
+
+	cfg.Targets = []string{"claude"}
+	kept := syncForState(t, dir, cfg, SyncOptions{KeepOrphans: true})
+	assert.Empty(t, linkStatus(kept, "cursor"))
+	_, err := os.Lstat(filepath.Join(dir, ".cursorrules"))
+	require.NoError(t, err)
+
+	// The manifest still lists the output, so a later run cleans it up
+	removed := syncForState(t, dir, cfg, SyncOptions{})
+	assert.Equal(t, "removed", linkStatus(removed, "cursor"))
+}
@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+)
+
+// Compose composes the configured overlays exactly as Sync does, without
+// writing anything. baseDir is the repository root; version is the
+// ailign version stamped into the managed header.
+func Compose(baseDir string, cfg *config.Config, version string) (*ComposeResult, error) {
+	if len(cfg.LocalOverlays) == 0 {
+		return nil, &InputError{Err: fmt.Errorf("no local_overlays configured in .ailign.yml")}
+	}
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving base directory: %w", err)
+	}
+	return ComposeOverlays(absBase, cfg.LocalOverlays, composeOptions(cfg, cfg.HubPath(), version))
+}
+
+// Render runs the full sync pipeline for a single target without writing
+// anything: composition, the secret scan that gates sync, and the
+// target's rendering. It returns exactly the content sync would give the
+// target's tool, along with composition warnings.
+func Render(baseDir string, cfg *config.Config, tgt target.Target, version string) ([]byte, []string, error) {
+	composed, err := Compose(baseDir, cfg, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	secretWarnings, err := checkSecrets(composed.Secrets, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings := append(composed.Warnings, secretWarnings...)
+	if cfg.OutputMode() == config.ModeSymlink {
+		if linksBreakViaSymlink(composed, cfg.HubPath(), tgt) {
+			warnings = append(warnings, symlinkLinkWarning(cfg.HubPath(), tgt))
+		}
+		if w := symlinkFormatWarning(cfg.HubPath(), tgt); w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+	return TargetContent(composed, cfg, tgt), warnings, nil
+}
+
+// TargetContent returns what a target's tool reads after sync: the hub
+// content itself in symlink mode, or the target's rendered copy in copy
+// mode.
+func TargetContent(composed *ComposeResult, cfg *config.Config, tgt target.Target) []byte {
+	if cfg.OutputMode() == config.ModeCopy {
+		return RenderTarget(composed, tgt, renderOptions(cfg, cfg.HubPath()))
+	}
+	return composed.Content
+}
+
+// RenderTarget returns the content a target sees at its InstructionPath
+// when written as a copy: the composed body with relative links rewritten
+// to resolve from the target's directory, under a managed header in the
+// target's format.
+func RenderTarget(composed *ComposeResult, tgt target.Target, opts RenderOptions) []byte {
+	body := rewriteLinks(string(composed.Body), path.Dir(opts.HubPath), path.Dir(tgt.InstructionPath()))
+	return []byte(targetHeader(composed, tgt, opts, body) + body)
+}
+
+// targetHeader returns the managed header for a target's rendered copy
+// of body, or "" when opts omits it.
+func targetHeader(composed *ComposeResult, tgt target.Target, opts RenderOptions, body string) string {
+	if opts.OmitHeader {
+		return ""
+	}
+	header := opts.Header
+	header.Format = tgt.Format()
+	return buildHeader(composed.Sources, header, composed.stamp(body))
+}
+
+// stamp returns the header stamp for body rendered from the result.
+func (r *ComposeResult) stamp(body string) HeaderStamp {
+	return HeaderStamp{Version: r.Version, Sources: r.SourcesDigest, Body: bodyDigest(body)}
+}
+
+// TargetSegments splits what TargetContent returns for tgt into the
+// managed header and the body attributed to source overlays.
+func TargetSegments(composed *ComposeResult, cfg *config.Config, tgt target.Target) (string, []Segment) {
+	if cfg.OutputMode() != config.ModeCopy {
+		return HubSegments(composed)
+	}
+	opts := renderOptions(cfg, cfg.HubPath())
+	fromDir, toDir := path.Dir(opts.HubPath), path.Dir(tgt.InstructionPath())
+	segs := make([]Segment, 0, len(composed.Segments))
+	for _, seg := range composed.Segments {
+		segs = append(segs, Segment{Overlay: seg.Overlay, Text: rewriteLinks(seg.Text, fromDir, toDir)})
+	}
+	body := rewriteLinks(string(composed.Body), fromDir, toDir)
+	return targetHeader(composed, tgt, opts, body), segs
+}
+
+// HubSegments splits the hub content into the managed header and the
+// body attributed to source overlays.
+func HubSegments(composed *ComposeResult) (string, []Segment) {
+	return string(composed.Content[:len(composed.Content)-len(composed.Body)]), composed.Segments
+}
+
+// symlinkLinkWarning explains that hub links break when read through
+// tgt's symlink.
+func symlinkLinkWarning(hubRelPath string, tgt target.Target) string {
+	return fmt.Sprintf("relative links in %s will not resolve from %s; set \"mode: copy\" to rewrite them per target",
+		hubRelPath, tgt.InstructionPath())
+}
+
+// symlinkFormatWarning explains that tgt reads the hub's managed header
+// through its symlink in the hub's format rather than its own, as when a
+// plain-text .cursorrules points at a Markdown hub. It returns "" when
+// the formats match.
+func symlinkFormatWarning(hubRelPath string, tgt target.Target) string {
+	hubFormat := formatForPath(hubRelPath)
+	if tgt.Format() == hubFormat {
+		return ""
+	}
+	return fmt.Sprintf("%s is read as %s but gets the %s header of %s through its symlink; set \"mode: copy\" to give it a header in its own format",
+		tgt.InstructionPath(), tgt.Format(), hubFormat, hubRelPath)
+}
+
+// linksBreakViaSymlink reports whether relative links in the hub content
+// would resolve differently when read through a symlink at the target's
+// InstructionPath.
+func linksBreakViaSymlink(composed *ComposeResult, hubRelPath string, tgt target.Target) bool {
+	rewritten := rewriteLinks(string(composed.Body), path.Dir(hubRelPath), path.Dir(tgt.InstructionPath()))
+	return !bytes.Equal([]byte(rewritten), composed.Body)
+}
@@ -0,0 +1,228 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ailign/cli/internal/config"
+)
+
+// secretRule is a credential pattern checked against composed content.
+type secretRule struct {
+	name        string
+	description string
+	pattern     *regexp.Regexp
+}
+
+// secretRules are checked in order. A span matched by one rule is not
+// reported again by later rules or by the entropy check.
+var secretRules = []secretRule{
+	{
+		name:        "private-key",
+		description: "private key",
+		pattern:     regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY(?: BLOCK)?-----.*?(?:-----END [A-Z ]*PRIVATE KEY(?: BLOCK)?-----|\z)`),
+	},
+	{
+		name:        "aws-access-key-id",
+		description: "AWS access key ID",
+		pattern:     regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`),
+	},
+	{
+		name:        "aws-secret-access-key",
+		description: "AWS secret access key",
+		pattern:     regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`),
+	},
+	{
+		name:        "github-token",
+		description: "GitHub token",
+		pattern:     regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`),
+	},
+	{
+		name:        "slack-token",
+		description: "Slack token",
+		pattern:     regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`),
+	},
+}
+
+// candidatePattern matches runs of base64/base64url characters that may
+// be an opaque credential.
+var candidatePattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+// Entropy threshold, in bits per character, above which a candidate
+// string is reported. Random base64 of this length scores about 4.7-5;
+// prose, identifiers and paths score well below 4.5.
+const entropyThreshold = 4.5
+
+// scanSecrets reports likely credentials in the composed body. Each
+// finding is traced back to the first overlay containing the matched
+// text; sources are the overlays' original contents, by name.
+func scanSecrets(body string, sources []overlaySource) []SecretFinding {
+	var findings []SecretFinding
+	var taken [][2]int
+
+	overlaps := func(start, end int) bool {
+		for _, t := range taken {
+			if start < t[1] && t[0] < end {
+				return true
+			}
+		}
+		return false
+	}
+	add := func(rule, desc string, start, end int) {
+		taken = append(taken, [2]int{start, end})
+		value := body[start:end]
+		f := SecretFinding{
+			Rule:        rule,
+			Description: desc,
+			Fingerprint: secretFingerprint(value),
+			Redacted:    redactSecret(value),
+		}
+		f.Overlay, f.Line = locateSecret(value, sources)
+		if f.Overlay == "" {
+			f.Line = strings.Count(body[:start], "\n") + 1
+		}
+		findings = append(findings, f)
+	}
+
+	for _, rule := range secretRules {
+		for _, m := range rule.pattern.FindAllStringIndex(body, -1) {
+			if !overlaps(m[0], m[1]) {
+				add(rule.name, rule.description, m[0], m[1])
+			}
+		}
+	}
+	for _, m := range candidatePattern.FindAllStringIndex(body, -1) {
+		value := body[m[0]:m[1]]
+		if overlaps(m[0], m[1]) || !mixedCharClasses(value) || shannonEntropy(value) < entropyThreshold {
+			continue
+		}
+		add("high-entropy", "high-entropy string", m[0], m[1])
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Overlay != findings[j].Overlay {
+			return findings[i].Overlay < findings[j].Overlay
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// overlaySource is an overlay's original content, used to trace findings
+// in composed output back to the file they came from.
+type overlaySource struct {
+	name    string
+	content string
+}
+
+// locateSecret returns the overlay and 1-based line where value first
+// appears, or "" and 0 when no overlay contains it verbatim.
+func locateSecret(value string, sources []overlaySource) (string, int) {
+	for _, src := range sources {
+		if i := strings.Index(src.content, value); i >= 0 {
+			return src.name, strings.Count(src.content[:i], "\n") + 1
+		}
+	}
+	return "", 0
+}
+
+// secretFingerprint identifies a finding by a truncated SHA-256 of the
+// matched text, so it can be allowlisted without repeating the secret.
+func secretFingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// redactSecret shows just enough of a match to recognize it.
+func redactSecret(value string) string {
+	if i := strings.IndexByte(value, '\n'); i >= 0 {
+		return value[:i] // first line of a private key block is its header
+	}
+	if len(value) <= 12 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", len(value)-8) + value[len(value)-4:]
+}
+
+// mixedCharClasses reports whether s contains an upper-case letter, a
+// lower-case letter and a digit, as generated credentials do and words,
+// hex digests and constants mostly don't.
+func mixedCharClasses(s string) bool {
+	var upper, lower, digit bool
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per byte.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var h float64
+	n := float64(len(s))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// checkSecrets fails when a finding is not allowlisted in cfg. Allowlist
+// entries that match no finding are returned as warnings so stale
+// entries get cleaned up.
+func checkSecrets(findings []SecretFinding, cfg *config.Config) ([]string, error) {
+	allowed := make(map[string]bool)
+	if cfg.Secrets != nil {
+		for _, entry := range cfg.Secrets.Allowlist {
+			allowed[entry.Fingerprint] = false
+		}
+	}
+
+	var blocked []string
+	for _, f := range findings {
+		if _, ok := allowed[f.Fingerprint]; ok {
+			allowed[f.Fingerprint] = true
+			continue
+		}
+		where := f.Overlay
+		if where == "" {
+			where = "composed content"
+		}
+		blocked = append(blocked, fmt.Sprintf("  %s:%d: possible %s %q (fingerprint %s)",
+			where, f.Line, f.Description, f.Redacted, f.Fingerprint))
+	}
+
+	var warnings []string
+	if cfg.Secrets != nil {
+		for _, entry := range cfg.Secrets.Allowlist {
+			if !allowed[entry.Fingerprint] {
+				warnings = append(warnings, fmt.Sprintf("secrets.allowlist entry %s matches no finding; remove it", entry.Fingerprint))
+				allowed[entry.Fingerprint] = true // warn once per fingerprint
+			}
+		}
+	}
+
+	if len(blocked) > 0 {
+		return warnings, &InputError{Err: fmt.Errorf("secret scan found %d potential secret(s) in overlays:\n%s\nRemove them from the overlays, or if a finding is a false positive, add its fingerprint under secrets.allowlist in .ailign.yml",
+			len(blocked), strings.Join(blocked, "\n"))}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,318 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+)
+
+// StatePath is the manifest, relative to the repository root, in which
+// sync records the files it manages. It describes one working tree, so
+// it is never committed: LocalIgnorePath keeps it out of git, and a fresh
+// clone finds hand edits through the managed headers instead.
+const StatePath = ".ailign/state.json"
+
+// stateFormat is the manifest format version. Manifests in any other
+// format are ignored and rewritten.
+const stateFormat = 1
+
+// Modes of a managed file in the state manifest.
+const (
+	StateModeHub     = "hub"
+	StateModeSymlink = config.ModeSymlink
+	StateModeCopy    = config.ModeCopy
+)
+
+// State is the manifest sync writes after every run that changed, or
+// confirmed, the files it manages.
+type State struct {
+	Format  int           `json:"format"`
+	Version string        `json:"ailign_version"` // CLI version that wrote the manifest
+	Config  string        `json:"config_digest"`  // digest of the effective configuration
+	Sources []StateSource `json:"sources"`        // overlays, in composition order
+	Files   []StateFile   `json:"files"`          // the hub first, then targets in config order
+	// Warnings are the warnings the run reported. A run with warnings is
+	// never skipped, so they keep being shown until they are fixed.
+	Warnings []string `json:"warnings,omitempty"`
+	// Partial is set when some configured targets were skipped or failed.
+	// Their entries, if any, are carried over from the previous manifest.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// StateSource records an overlay's content digest.
+type StateSource struct {
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+}
+
+// StateFile records a file sync manages.
+type StateFile struct {
+	Path   string `json:"path"`             // slash-separated, relative to the repository root
+	Target string `json:"target,omitempty"` // empty for the hub
+	Mode   string `json:"mode"`             // StateModeHub, StateModeSymlink or StateModeCopy
+	Link   string `json:"link,omitempty"`   // symlink destination as written, for symlinks
+	Digest string `json:"digest"`           // digest of the content read through Path
+	// Dirs lists the directories sync created to hold the file, deepest
+	// first, so removing an orphan leaves directories the user made alone.
+	Dirs []string `json:"dirs,omitempty"`
+}
+
+// ReadState loads the manifest for the repository at baseDir. It returns
+// nil and no error if there is none or it was written in another format.
+func ReadState(baseDir string) (*State, error) {
+	data, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(StatePath)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", StatePath, err)
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StatePath, err)
+	}
+	if st.Format != stateFormat {
+		return nil, nil
+	}
+	return &st, nil
+}
+
+// writeState saves the manifest for the repository at baseDir.
+func writeState(baseDir string, st *State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(baseDir, filepath.FromSlash(StatePath)), append(data, '\n'))
+}
+
+// File returns the manifest entry for target, or for the hub when target
+// is empty, or nil if there is none.
+func (s *State) File(target string) *StateFile {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Files {
+		if s.Files[i].Target == target {
+			return &s.Files[i]
+		}
+	}
+	return nil
+}
+
+// contentDigest returns the digest recorded for content in the manifest.
+func contentDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// configDigest returns a digest of the effective configuration.
+func configDigest(cfg *config.Config) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encoding config: %w", err)
+	}
+	return contentDigest(data), nil
+}
+
+// sourceDigests reads every overlay and returns its digest.
+func sourceDigests(baseDir string, overlays []string) ([]StateSource, error) {
+	sources := make([]StateSource, 0, len(overlays))
+	for _, overlay := range overlays {
+		data, err := os.ReadFile(filepath.Join(baseDir, overlay))
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, StateSource{Path: overlay, Digest: contentDigest(data)})
+	}
+	return sources, nil
+}
+
+// describeFile records the current state of a managed file.
+func describeFile(baseDir, relPath, target, mode string) (StateFile, error) {
+	p := filepath.Join(baseDir, filepath.FromSlash(relPath))
+	f := StateFile{Path: filepath.ToSlash(relPath), Target: target, Mode: mode}
+	if mode == StateModeSymlink {
+		link, err := os.Readlink(p)
+		if err != nil {
+			return f, err
+		}
+		f.Link = link
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return f, err
+	}
+	f.Digest = contentDigest(data)
+	return f, nil
+}
+
+// Intact reports whether the file is still exactly as sync left it: the
+// same kind of file, pointing at the same place, with the same content.
+func (f StateFile) Intact(baseDir string) bool {
+	info, err := os.Lstat(filepath.Join(baseDir, filepath.FromSlash(f.Path)))
+	if err != nil {
+		return false
+	}
+	isLink := info.Mode()&os.ModeSymlink != 0
+	if isLink != (f.Mode == StateModeSymlink) || (!isLink && !info.Mode().IsRegular()) {
+		return false
+	}
+	current, err := describeFile(baseDir, f.Path, f.Target, f.Mode)
+	return err == nil && current.Link == f.Link && current.Digest == f.Digest
+}
+
+// sameSources reports whether two source lists are identical.
+func sameSources(a, b []StateSource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// currentState returns the manifest fields describing this run's inputs.
+// Sources is nil if an overlay can't be read; composition reports why.
+func currentState(baseDir string, cfg *config.Config, version string) (*State, error) {
+	digest, err := configDigest(cfg)
+	if err != nil {
+		return nil, err
+	}
+	st := &State{Format: stateFormat, Version: version, Config: digest}
+	st.Sources, _ = sourceDigests(baseDir, cfg.LocalOverlays)
+	return st, nil
+}
+
+// upToDate reports whether prev was written by a complete run without
+// warnings from the same inputs as cur, lists exactly the hub and the
+// configured targets, and every file it lists is still intact.
+func upToDate(baseDir string, prev, cur *State, cfg *config.Config, registry *target.Registry) bool {
+	if prev == nil || prev.Partial || len(prev.Warnings) > 0 ||
+		prev.Version != cur.Version || prev.Config != cur.Config || !sameSources(prev.Sources, cur.Sources) {
+		return false
+	}
+	if len(prev.Files) != len(cfg.Targets)+1 {
+		return false // orphans kept by an earlier run are still to be removed
+	}
+	if hub := prev.File(""); hub == nil || hub.Path != cfg.HubPath() || !hub.Intact(baseDir) {
+		return false
+	}
+	for _, name := range cfg.Targets {
+		tgt, ok := registry.Get(name)
+		f := prev.File(name)
+		if !ok || f == nil || f.Path != filepath.ToSlash(tgt.InstructionPath()) ||
+			f.Mode != cfg.OutputMode() || !f.Intact(baseDir) {
+			return false
+		}
+	}
+	return true
+}
+
+// upToDateResult reports a run skipped by upToDate.
+func upToDateResult(cfg *config.Config, registry *target.Registry, selected map[string]bool, hubPath string, opts SyncOptions) *SyncResult {
+	result := &SyncResult{
+		DryRun:    opts.DryRun,
+		HubPath:   hubPath,
+		HubStatus: "unchanged",
+		Links:     make([]LinkResult, 0, len(cfg.Targets)),
+		Warnings:  []string{},
+		UpToDate:  true,
+	}
+	for _, name := range cfg.Targets {
+		tgt, _ := registry.Get(name)
+		link := LinkResult{Target: name, LinkPath: tgt.InstructionPath(), Mode: cfg.OutputMode(), Status: "exists"}
+		if !selected[name] {
+			link.Status = "skipped"
+		}
+		result.Links = append(result.Links, link)
+	}
+	return result
+}
+
+// recordState removes outputs of targets dropped from the configuration,
+// unless keepOrphans is set, and writes the manifest for this run.
+// Entries of targets that were skipped or failed are carried over from
+// prev. createdDirs holds the directories each target's write created;
+// they are recorded with the target's entry. Problems recording state
+// are reported as warnings: the outputs themselves are already in place.
+// A dry run only reports what would be removed.
+func recordState(baseDir string, prev, cur *State, cfg *config.Config, registry *target.Registry, result *SyncResult, createdDirs map[string][]string, keepOrphans bool) {
+	st := *cur
+	st.Warnings = append([]string(nil), result.Warnings...)
+	mode := cfg.OutputMode()
+
+	claimed := map[string]bool{cfg.HubPath(): true}
+	if !result.DryRun {
+		hub, err := describeFile(baseDir, cfg.HubPath(), "", StateModeHub)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("recording sync state: %v", err))
+			return
+		}
+		st.Files = append(st.Files, hub)
+	}
+	for _, link := range result.Links {
+		tgt, ok := registry.Get(link.Target)
+		if !ok {
+			st.Partial = true
+			continue
+		}
+		relPath := filepath.ToSlash(tgt.InstructionPath())
+		claimed[relPath] = true
+		if result.DryRun {
+			continue
+		}
+		switch link.Status {
+		case "created", "exists", "replaced":
+			f, err := describeFile(baseDir, relPath, link.Target, mode)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("recording sync state: %v", err))
+				st.Partial = true
+				continue
+			}
+			f.Dirs = createdDirs[link.Target]
+			if old := prev.File(link.Target); len(f.Dirs) == 0 && old != nil && old.Path == f.Path {
+				f.Dirs = old.Dirs
+			}
+			st.Files = append(st.Files, f)
+		default:
+			st.Partial = true
+			if f := prev.File(link.Target); f != nil {
+				st.Files = append(st.Files, *f)
+			}
+		}
+	}
+
+	for _, f := range findOrphans(baseDir, prev, cfg, registry, claimed) {
+		if keepOrphans {
+			if prev.File(f.Target) != nil {
+				st.Files = append(st.Files, f) // still removed by a later run
+			}
+			continue
+		}
+		link, warning := removeOrphan(baseDir, f, result.DryRun)
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+		if link != nil {
+			result.Links = append(result.Links, *link)
+		}
+	}
+
+	if result.DryRun {
+		return
+	}
+	if err := writeState(baseDir, &st); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("recording sync state: %v", err))
+	}
+}
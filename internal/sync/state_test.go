@@ -0,0 +1,125 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func syncForState(t *testing.T, dir string, cfg *config.Config, opts SyncOptions) *SyncResult {
+	t.Helper()
+	result, err := Sync(dir, cfg, target.NewDefaultRegistry(), opts)
+	require.NoError(t, err)
+	return result
+}
+
+func TestSync_WritesState(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}}
+
+	syncForState(t, dir, cfg, SyncOptions{Version: "1.2.3"})
+
+	st, err := ReadState(dir)
+	require.NoError(t, err)
+	require.NotNil(t, st)
+	assert.Equal(t, "1.2.3", st.Version)
+	assert.Equal(t, []StateSource{{Path: "base.md", Digest: contentDigest([]byte("# Base\n"))}}, st.Sources)
+	assert.False(t, st.Partial)
+
+	require.Len(t, st.Files, 3)
+	hub, err := os.ReadFile(filepath.Join(dir, ".ailign", "instructions.md"))
+	require.NoError(t, err)
+	assert.Equal(t, StateFile{Path: ".ailign/instructions.md", Mode: StateModeHub, Digest: contentDigest(hub)}, st.Files[0])
+	assert.Equal(t, StateFile{
+		Path: ".claude/instructions.md", Target: "claude", Mode: StateModeSymlink,
+		Link: "../.ailign/instructions.md", Digest: contentDigest(hub), Dirs: []string{".claude"},
+	}, st.Files[1])
+	assert.Equal(t, "cursor", st.Files[2].Target)
+}
+
+func TestSync_StateSkipsUnchangedRuns(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}}
+
+	first := syncForState(t, dir, cfg, SyncOptions{})
+	assert.False(t, first.UpToDate)
+
+	again := syncForState(t, dir, cfg, SyncOptions{})
+	assert.True(t, again.UpToDate)
+	assert.Equal(t, "unchanged", again.HubStatus)
+	for _, l := range again.Links {
+		assert.Equal(t, "exists", l.Status)
+	}
+
+	// A missing output is recreated
+	require.NoError(t, os.Remove(filepath.Join(dir, ".cursorrules")))
+	repaired := syncForState(t, dir, cfg, SyncOptions{})
+	assert.False(t, repaired.UpToDate)
+	assert.Equal(t, "created", repaired.Links[1].Status)
+
+	// A changed overlay is recomposed
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nMore.\n")
+	changed := syncForState(t, dir, cfg, SyncOptions{})
+	assert.False(t, changed.UpToDate)
+	assert.Equal(t, "written", changed.HubStatus)
+
+	// So is a new CLI version
+	upgraded := syncForState(t, dir, cfg, SyncOptions{Version: "2.0.0"})
+	assert.False(t, upgraded.UpToDate)
+}
+
+func TestSync_StateNeverSkipsRunsWithWarnings(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "empty.md"), "")
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"empty.md"}, Mode: config.ModeCopy}
+
+	syncForState(t, dir, cfg, SyncOptions{})
+	again := syncForState(t, dir, cfg, SyncOptions{})
+
+	assert.False(t, again.UpToDate)
+	assert.Contains(t, again.Warnings, "overlay empty.md is empty")
+}
+
+func TestSync_StatePartialRun(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}}
+
+	syncForState(t, dir, cfg, SyncOptions{})
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n\nMore.\n")
+	syncForState(t, dir, cfg, SyncOptions{Targets: []string{"claude"}})
+
+	st, err := ReadState(dir)
+	require.NoError(t, err)
+	assert.True(t, st.Partial)
+	assert.NotNil(t, st.File("cursor"), "skipped target keeps its entry")
+
+	again := syncForState(t, dir, cfg, SyncOptions{})
+	assert.False(t, again.UpToDate, "a partial manifest never skips work")
+}
+
+func TestSync_CorruptStateIsReplaced(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "# Base\n")
+	writeFile(t, filepath.Join(dir, ".ailign", "state.json"), "{not json")
+	cfg := &config.Config{Targets: []string{"claude"}, LocalOverlays: []string{"base.md"}, Mode: config.ModeCopy}
+
+	result := syncForState(t, dir, cfg, SyncOptions{})
+	require.NotEmpty(t, result.Warnings)
+	assert.Contains(t, result.Warnings[0], "ignoring state manifest: parsing .ailign/state.json")
+
+	st, err := ReadState(dir)
+	require.NoError(t, err)
+	require.NotNil(t, st)
+	assert.Equal(t, StateModeCopy, st.File("claude").Mode)
+}
@@ -2,21 +2,52 @@ package sync
 
 import (
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/ailign/cli/internal/config"
 	"github.com/ailign/cli/internal/target"
 )
 
-const hubRelPath = ".ailign/instructions.md"
-
-// Sync composes overlays and syncs to all configured targets.
+// Sync composes overlays and syncs to all configured targets, either
+// as symlinks to the hub file or, in copy mode, as rendered copies.
 // Returns a SyncResult with per-target outcomes. Partial failures
 // (e.g., one target's symlink fails) are captured in LinkResult,
 // not as an overall error.
+//
+// Sync fails before writing anything if the composed content contains a
+// likely secret that is not allowlisted in config. With opts.Atomic, a
+// failing target instead rolls back every change the run made; see
+// applyAtomically.
+//
+// Unless opts.DryRun is set, Sync holds the lock at LockPath for the
+// whole run and returns a *LockedError if another process keeps it
+// longer than opts.LockTimeout.
+//
+// After each run Sync records the files it manages in the state manifest
+// at StatePath. If the manifest shows that the configuration, overlays,
+// CLI version and every output are unchanged since a complete run
+// without warnings, composition and writes are skipped.
+//
+// Outputs of targets that are no longer configured are removed and
+// reported with status "removed", unless opts.KeepOrphans is set; see
+// findOrphans.
+//
+// A hub or target file whose content no longer matches the digest in
+// the manifest was edited outside ailign; Sync returns a *ModifiedError
+// naming the overlays the edits belong in rather than overwrite it,
+// unless opts.Force is set. This applies to dry runs too.
+//
+// With cfg.CommitOutputs set, Sync also keeps an ailign block listing
+// the outputs in .gitignore or .gitattributes; see syncGitFiles.
+//
+// Errors caused by the configuration or overlays are *InputError;
+// anything else is an I/O failure.
 func Sync(baseDir string, cfg *config.Config, registry *target.Registry, opts SyncOptions) (*SyncResult, error) {
 	if len(cfg.LocalOverlays) == 0 {
-		return nil, fmt.Errorf("no local_overlays configured in .ailign.yml")
+		return nil, &InputError{Err: fmt.Errorf("no local_overlays configured in .ailign.yml")}
 	}
 
 	// Normalize to absolute path so derived paths satisfy EnsureSymlink's contract
@@ -26,37 +57,83 @@ func Sync(baseDir string, cfg *config.Config, registry *target.Registry, opts Sy
 	}
 	baseDir = absBase
 
-	hubPath := filepath.Join(baseDir, hubRelPath)
+	selected, err := selectTargets(cfg.Targets, registry, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Compose overlays
-	composed, err := ComposeOverlays(baseDir, cfg.LocalOverlays)
+	hubRelPath := cfg.HubPath()
+	if err := validateHubPath(baseDir, hubRelPath, cfg, registry); err != nil {
+		return nil, &InputError{Err: err}
+	}
+	hubPath := filepath.Join(baseDir, filepath.FromSlash(hubRelPath))
+	formatWarnings := symlinkFormatWarnings(cfg, registry, selected)
+
+	// Hold the lock from reading overlays to the last write
+	if !opts.DryRun {
+		lock, err := AcquireLock(baseDir, opts.LockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = lock.Release() }()
+	}
+
+	// Skip everything if the manifest shows nothing changed
+	prev, stateErr := ReadState(baseDir)
+	cur, err := currentState(baseDir, cfg, opts.Version)
 	if err != nil {
 		return nil, err
 	}
+	if cur.Sources != nil && upToDate(baseDir, prev, cur, cfg, registry) {
+		result := upToDateResult(cfg, registry, selected, hubPath, opts)
+		result.GitFiles = syncGitFiles(baseDir, cfg, registry, opts.DryRun)
+		result.Sources = cur.Sources
+		result.Warnings = append(result.Warnings, formatWarnings...)
+		return result, nil
+	}
 
-	// Write or check hub file
-	var hubStatus string
-	if opts.DryRun {
-		hubStatus, err = CheckHubStatus(hubPath, composed.Content)
-	} else {
-		hubStatus, err = WriteHub(hubPath, composed.Content)
+	// Compose overlays
+	composed, err := ComposeOverlays(baseDir, cfg.LocalOverlays, composeOptions(cfg, hubRelPath, opts.Version))
+	if err != nil {
+		return nil, err
 	}
+
+	// Refuse to write anything that looks like a credential
+	secretWarnings, err := checkSecrets(composed.Secrets, cfg)
 	if err != nil {
-		if opts.DryRun {
-			return nil, fmt.Errorf("checking hub file: %w", err)
+		return nil, err
+	}
+
+	// Refuse to overwrite edits made outside ailign
+	if !opts.Force {
+		if modified := findModified(baseDir, prev, composed, cfg, registry, selected); len(modified) > 0 {
+			return nil, &ModifiedError{Files: modified}
 		}
-		return nil, fmt.Errorf("writing hub file: %w", err)
 	}
 
 	result := &SyncResult{
-		DryRun:    opts.DryRun,
-		HubPath:   hubPath,
-		HubStatus: hubStatus,
-		Links:     make([]LinkResult, 0, len(cfg.Targets)),
-		Warnings:  composed.Warnings,
+		DryRun:   opts.DryRun,
+		HubPath:  hubPath,
+		Links:    make([]LinkResult, 0, len(cfg.Targets)),
+		Warnings: append(composed.Warnings, secretWarnings...),
+		Sources:  cur.Sources,
+	}
+	if stateErr != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("ignoring state manifest: %v", stateErr))
+	}
+
+	hub := syncStep{
+		path:  hubPath,
+		check: func() (string, error) { return CheckHubStatus(hubPath, composed.Content) },
+		apply: func() (string, error) { return WriteHub(hubPath, composed.Content) },
 	}
 
-	// Create or check symlinks per target
+	mode := cfg.OutputMode()
+
+	// Plan a symlink (or copy) per target
+	var steps []syncStep
+	var stepLinks []int                  // index in result.Links of each step
+	createdDirs := map[string][]string{} // directories each target's write creates
 	for _, targetName := range cfg.Targets {
 		tgt, ok := registry.Get(targetName)
 		if !ok {
@@ -69,26 +146,231 @@ func Sync(baseDir string, cfg *config.Config, registry *target.Registry, opts Sy
 			continue
 		}
 
+		link := LinkResult{
+			Target:   targetName,
+			LinkPath: tgt.InstructionPath(),
+			Mode:     mode,
+		}
+		if !selected[targetName] {
+			link.Status = "skipped"
+			result.Links = append(result.Links, link)
+			continue
+		}
+
 		linkPath := filepath.Join(baseDir, tgt.InstructionPath())
-		var status string
-		if opts.DryRun {
-			status, err = CheckSymlinkStatus(linkPath, hubPath)
+		createdDirs[targetName] = missingDirs(baseDir, linkPath)
+		if mode == config.ModeCopy {
+			content := TargetContent(composed, cfg, tgt)
+			steps = append(steps, syncStep{
+				path:  linkPath,
+				check: func() (string, error) { return CheckCopyStatus(linkPath, content) },
+				apply: func() (string, error) { return EnsureCopy(linkPath, content) },
+			})
 		} else {
-			status, err = EnsureSymlink(linkPath, hubPath)
+			if linksBreakViaSymlink(composed, hubRelPath, tgt) {
+				result.Warnings = append(result.Warnings, symlinkLinkWarning(hubRelPath, tgt))
+			}
+			steps = append(steps, syncStep{
+				path:  linkPath,
+				check: func() (string, error) { return CheckSymlinkStatus(linkPath, hubPath) },
+				apply: func() (string, error) { return EnsureSymlink(linkPath, hubPath) },
+			})
 		}
+		stepLinks = append(stepLinks, len(result.Links))
+		result.Links = append(result.Links, link)
+	}
 
-		link := LinkResult{
-			Target:   targetName,
-			LinkPath: tgt.InstructionPath(),
+	if opts.Atomic && !opts.DryRun {
+		var gitSteps []syncStep
+		gitSteps, result.GitFiles = gitFileSteps(baseDir, cfg, registry)
+		if err := applyAtomically(result, hub, steps, stepLinks, gitSteps); err != nil {
+			return nil, err
 		}
-		if err != nil {
+		if !result.RolledBack {
+			recordState(baseDir, prev, cur, cfg, registry, result, createdDirs, opts.KeepOrphans)
+		}
+		result.Warnings = append(result.Warnings, formatWarnings...)
+		return result, nil
+	}
+
+	// Write or check hub file
+	if opts.DryRun {
+		result.HubStatus, err = hub.check()
+	} else {
+		result.HubStatus, err = hub.apply()
+	}
+	if err != nil {
+		if opts.DryRun {
+			return nil, fmt.Errorf("checking hub file: %w", err)
+		}
+		return nil, fmt.Errorf("writing hub file: %w", err)
+	}
+
+	// Create or check each target, recording failures per link
+	for i, step := range steps {
+		run := step.apply
+		if opts.DryRun {
+			run = step.check
+		}
+		link := &result.Links[stepLinks[i]]
+		if status, err := run(); err != nil {
 			link.Status = "error"
 			link.Error = err.Error()
 		} else {
 			link.Status = status
 		}
-		result.Links = append(result.Links, link)
 	}
 
+	recordState(baseDir, prev, cur, cfg, registry, result, createdDirs, opts.KeepOrphans)
+	result.GitFiles = syncGitFiles(baseDir, cfg, registry, opts.DryRun)
+	result.Warnings = append(result.Warnings, formatWarnings...)
 	return result, nil
 }
+
+// symlinkFormatWarnings returns the symlinkFormatWarning of every
+// selected target in symlink mode. They follow from the configuration
+// alone, so they are reported by skipped runs too, and are left out of
+// the state manifest, where they would keep later runs from being
+// skipped.
+func symlinkFormatWarnings(cfg *config.Config, registry *target.Registry, selected map[string]bool) []string {
+	if cfg.OutputMode() != config.ModeSymlink {
+		return nil
+	}
+	var warnings []string
+	for _, name := range cfg.Targets {
+		tgt, ok := registry.Get(name)
+		if !ok || !selected[name] {
+			continue
+		}
+		if w := symlinkFormatWarning(cfg.HubPath(), tgt); w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// selectTargets returns the set of configured targets the run covers.
+// Names given in opts must be known and configured, and a target cannot
+// be both selected and skipped.
+func selectTargets(configured []string, registry *target.Registry, opts SyncOptions) (map[string]bool, error) {
+	isConfigured := make(map[string]bool, len(configured))
+	for _, name := range configured {
+		isConfigured[name] = true
+	}
+
+	var errs []string
+	check := func(verb string, names []string) map[string]bool {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			switch {
+			case !registry.IsValid(name):
+				errs = append(errs, fmt.Sprintf("cannot %s target %q: unknown target (expected one of: %s)",
+					verb, name, strings.Join(registry.KnownTargets(), ", ")))
+			case !isConfigured[name]:
+				errs = append(errs, fmt.Sprintf("cannot %s target %q: not configured in .ailign.yml", verb, name))
+			}
+			set[name] = true
+		}
+		return set
+	}
+	only := check("select", opts.Targets)
+	skip := check("skip", opts.SkipTargets)
+	for _, name := range opts.SkipTargets {
+		if only[name] {
+			errs = append(errs, fmt.Sprintf("target %q is both selected and skipped", name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &InputError{Err: fmt.Errorf("%s", strings.Join(errs, "\n"))}
+	}
+
+	selected := make(map[string]bool, len(configured))
+	for _, name := range configured {
+		if (len(only) == 0 || only[name]) && !skip[name] {
+			selected[name] = true
+		}
+	}
+	return selected, nil
+}
+
+// composeOptions derives composition options from the config. Links are
+// rewritten to resolve from the directory of the hub file, and the
+// header style follows the hub file's extension.
+func composeOptions(cfg *config.Config, hubRelPath, version string) ComposeOptions {
+	opts := ComposeOptions{
+		OutputDir: path.Dir(hubRelPath),
+		Header:    headerOptions(cfg),
+		Version:   version,
+	}
+	opts.Header.Format = formatForPath(hubRelPath)
+	if cfg.Compose != nil {
+		opts.Dedupe = cfg.Compose.Dedupe
+	}
+	if cfg.Security != nil {
+		opts.HiddenUnicode = cfg.Security.HiddenUnicode
+		opts.PromptInjection = cfg.Security.PromptInjection
+	}
+	return opts
+}
+
+// renderOptions derives per-target rendering options for copy mode.
+func renderOptions(cfg *config.Config, hubRelPath string) RenderOptions {
+	opts := RenderOptions{HubPath: hubRelPath, Header: headerOptions(cfg)}
+	if cfg.Hub != nil && cfg.Hub.Header != nil && cfg.Hub.Header.InCopies != nil {
+		opts.OmitHeader = !*cfg.Hub.Header.InCopies
+	}
+	return opts
+}
+
+// headerOptions derives header customization from the config.
+func headerOptions(cfg *config.Config) HeaderOptions {
+	var opts HeaderOptions
+	if cfg.Hub != nil && cfg.Hub.Header != nil {
+		opts.Text = cfg.Hub.Header.Text
+		opts.Link = cfg.Hub.Header.Link
+	}
+	return opts
+}
+
+// validateHubPath checks that the configured hub path is relative, stays
+// inside the repository, and doesn't collide with a target's instruction
+// file (which would make the target a symlink to itself) or with an
+// overlay (which sync would overwrite with its own output).
+func validateHubPath(baseDir, hubRelPath string, cfg *config.Config, registry *target.Registry) error {
+	if filepath.IsAbs(hubRelPath) || filepath.VolumeName(hubRelPath) != "" || strings.HasPrefix(hubRelPath, "/") {
+		return fmt.Errorf("hub path must be relative to the repository root: %s", hubRelPath)
+	}
+	cleaned := path.Clean(filepath.ToSlash(hubRelPath))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("hub path traversal rejected: %s", hubRelPath)
+	}
+	for _, name := range cfg.Targets {
+		if tgt, ok := registry.Get(name); ok && path.Clean(tgt.InstructionPath()) == cleaned {
+			return fmt.Errorf("hub path %s collides with the %s target's instruction file", hubRelPath, name)
+		}
+	}
+	hubPath := filepath.Join(baseDir, filepath.FromSlash(cleaned))
+	for _, overlay := range cfg.LocalOverlays {
+		if sameFile(hubPath, filepath.Join(baseDir, overlay)) {
+			return fmt.Errorf("hub path %s is the overlay %s, which sync would overwrite", hubRelPath, overlay)
+		}
+	}
+	return nil
+}
+
+// sameFile reports whether a and b name the same file, either lexically
+// or, when both exist, after following symlinks.
+func sameFile(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
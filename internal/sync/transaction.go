@@ -0,0 +1,251 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// syncStep is one change sync makes: writing the hub or a target's link
+// or copy. check reports what apply would do without touching disk.
+type syncStep struct {
+	path  string
+	check func() (string, error)
+	apply func() (string, error)
+}
+
+// applyAtomically applies the hub, target and git file steps
+// all-or-nothing. gitSteps report into result.GitFiles, one entry each;
+// entries left unchanged are dropped from it before returning.
+//
+// Every step is checked first and the current state of each path that
+// will change is snapshotted; nothing is modified if a check fails.
+// Steps are then applied in order. If any step fails, every snapshot is
+// restored in reverse order, planned changes are reported with status
+// "rolled_back" and result.RolledBack is set. Paths that could not be
+// restored are listed in result.RollbackErrors.
+//
+// The returned error reports a step that could not be checked or
+// staged, in which case nothing was written, or a failure to write the
+// hub. Either aborts the run, as a hub failure does in a normal sync.
+func applyAtomically(result *SyncResult, hub syncStep, steps []syncStep, stepLinks []int, gitSteps []syncStep) error {
+	var tx transaction
+
+	hubStatus, err := hub.check()
+	if err != nil {
+		return fmt.Errorf("checking hub file: %w", err)
+	}
+	result.HubStatus = hubStatus
+	if hubStatus != "unchanged" {
+		if err := tx.stage(hub.path); err != nil {
+			return fmt.Errorf("staging hub file: %w", err)
+		}
+	}
+
+	// Stage: record what each step would do and snapshot what it changes
+	var stageErrs []error
+	for i, step := range steps {
+		link := &result.Links[stepLinks[i]]
+		status, err := step.check()
+		if err == nil && status != "exists" {
+			err = tx.stage(step.path)
+		}
+		if err != nil {
+			stageErrs = append(stageErrs, fmt.Errorf("%s: %w", link.Target, err))
+			continue
+		}
+		link.Status = status
+	}
+	for i, step := range gitSteps {
+		status, err := step.check()
+		if err == nil && status != "unchanged" {
+			err = tx.stage(step.path)
+		}
+		if err != nil {
+			// updateBlock's errors already name the file
+			stageErrs = append(stageErrs, err)
+			continue
+		}
+		result.GitFiles[i].Status = status
+	}
+	if len(stageErrs) > 0 {
+		return fmt.Errorf("staging changes (nothing was written): %w", errors.Join(stageErrs...))
+	}
+
+	// Apply, stopping at the first failure
+	failed := false
+	if hubStatus != "unchanged" {
+		tx.applied++
+		if _, err := hub.apply(); err != nil {
+			if errs := tx.rollback(); len(errs) > 0 {
+				return fmt.Errorf("writing hub file: %w (rollback failed: %s)", err, strings.Join(errs, "; "))
+			}
+			return fmt.Errorf("writing hub file: %w", err)
+		}
+	}
+	for i, step := range steps {
+		if failed {
+			break
+		}
+		link := &result.Links[stepLinks[i]]
+		if link.Status == "exists" {
+			continue
+		}
+		tx.applied++
+		if _, err := step.apply(); err != nil {
+			link.Status = "error"
+			link.Error = err.Error()
+			failed = true
+		}
+	}
+	for i, step := range gitSteps {
+		if failed {
+			break
+		}
+		gitFile := &result.GitFiles[i]
+		if gitFile.Status == "unchanged" {
+			continue
+		}
+		tx.applied++
+		if _, err := step.apply(); err != nil {
+			gitFile.Status = "error"
+			gitFile.Error = err.Error()
+			failed = true
+		}
+	}
+	result.GitFiles = changedGitFiles(result.GitFiles)
+	if !failed {
+		return nil
+	}
+
+	result.RolledBack = true
+	result.RollbackErrors = tx.rollback()
+	if result.HubStatus == "written" {
+		result.HubStatus = "rolled_back"
+	}
+	for _, i := range stepLinks {
+		if s := result.Links[i].Status; s == "created" || s == "replaced" {
+			result.Links[i].Status = "rolled_back"
+		}
+	}
+	for i := range result.GitFiles {
+		if s := result.GitFiles[i].Status; s == "written" || s == "removed" {
+			result.GitFiles[i].Status = "rolled_back"
+		}
+	}
+	return nil
+}
+
+// changedGitFiles drops the git files whose block was left unchanged.
+func changedGitFiles(files []GitFileResult) []GitFileResult {
+	var changed []GitFileResult
+	for _, f := range files {
+		if f.Status != "unchanged" {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// transaction holds snapshots of paths sync is about to change, in the
+// order the changes are applied.
+type transaction struct {
+	snapshots []snapshot
+	applied   int // number of snapshots whose change has been attempted
+}
+
+// snapshot is the state of a path before sync changed it.
+type snapshot struct {
+	path    string
+	exists  bool
+	symlink bool
+	link    string // symlink target, when the path was a symlink
+	content []byte // file content, when the path was a regular file
+	perm    os.FileMode
+	newDirs []string // missing parent directories sync may create, innermost first
+}
+
+// stage snapshots path so rollback can restore it. Only symlinks,
+// regular files and missing paths can be staged.
+func (tx *transaction) stage(path string) error {
+	snap := snapshot{path: path}
+
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+			if _, err := os.Lstat(dir); err == nil || !errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			snap.newDirs = append(snap.newDirs, dir)
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	case err != nil:
+		return fmt.Errorf("snapshotting %s: %w", path, err)
+	case info.Mode()&os.ModeSymlink != 0:
+		snap.exists = true
+		snap.symlink = true
+		if snap.link, err = os.Readlink(path); err != nil {
+			return fmt.Errorf("snapshotting %s: %w", path, err)
+		}
+	case info.Mode().IsRegular():
+		snap.exists = true
+		snap.perm = info.Mode().Perm()
+		if snap.content, err = os.ReadFile(path); err != nil {
+			return fmt.Errorf("snapshotting %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("cannot stage %s: not a regular file or symlink", path)
+	}
+
+	tx.snapshots = append(tx.snapshots, snap)
+	return nil
+}
+
+// rollback restores, in reverse order, every snapshot whose change was
+// attempted and returns the errors of those that could not be restored.
+func (tx *transaction) rollback() []string {
+	var errs []string
+	for i := tx.applied - 1; i >= 0; i-- {
+		if err := tx.snapshots[i].restore(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+// restore puts the snapshotted path back into its previous state.
+func (s snapshot) restore() error {
+	if !s.exists || s.symlink {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("restoring %s: %w", s.path, err)
+		}
+	}
+
+	switch {
+	case !s.exists:
+		for _, dir := range s.newDirs {
+			// Only empty directories are removed; another snapshot may
+			// still need a shared parent, and it is restored later.
+			_ = os.Remove(dir)
+		}
+		return nil
+	case s.symlink:
+		if err := os.Symlink(s.link, s.path); err != nil {
+			return fmt.Errorf("restoring %s: %w", s.path, err)
+		}
+		return nil
+	default:
+		if err := writeFileAtomic(s.path, s.content); err != nil {
+			return fmt.Errorf("restoring %s: %w", s.path, err)
+		}
+		if err := os.Chmod(s.path, s.perm); err != nil {
+			return fmt.Errorf("restoring %s: %w", s.path, err)
+		}
+		return nil
+	}
+}
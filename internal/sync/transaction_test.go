@@ -0,0 +1,200 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ailign/cli/internal/config"
+	"github.com/ailign/cli/internal/target"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// transaction tests
+// ---------------------------------------------------------------------------
+
+func TestTransaction_RestoresPreviousState(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	file := filepath.Join(dir, "file.md")
+	link := filepath.Join(dir, "link.md")
+	missing := filepath.Join(dir, "new", "nested", "missing.md")
+	require.NoError(t, os.WriteFile(file, []byte("original"), 0600))
+	require.NoError(t, os.Symlink("file.md", link))
+
+	var tx transaction
+	for _, p := range []string{file, link, missing} {
+		require.NoError(t, tx.stage(p))
+		tx.applied++
+	}
+
+	require.NoError(t, os.WriteFile(file, []byte("changed"), 0644))
+	require.NoError(t, os.Remove(link))
+	require.NoError(t, os.WriteFile(link, []byte("now a file"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Dir(missing), 0755))
+	require.NoError(t, os.WriteFile(missing, []byte("created"), 0644))
+
+	assert.Empty(t, tx.rollback())
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	dest, err := os.Readlink(link)
+	require.NoError(t, err)
+	assert.Equal(t, "file.md", dest)
+
+	_, err = os.Stat(filepath.Join(dir, "new"))
+	assert.True(t, os.IsNotExist(err), "directories created by the change are removed")
+}
+
+func TestTransaction_RejectsDirectories(t *testing.T) {
+	dir := t.TempDir()
+	var tx transaction
+	err := tx.stage(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a regular file or symlink")
+}
+
+// ---------------------------------------------------------------------------
+// Atomic sync tests
+// ---------------------------------------------------------------------------
+
+func TestSync_AtomicRollsBackOnFailure(t *testing.T) {
+	skipOnWindows(t)
+	if os.Geteuid() == 0 {
+		t.Skip("read-only directories are writable by root")
+	}
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "New content\n")
+	hubPath := filepath.Join(dir, ".ailign", "instructions.md")
+	writeFile(t, hubPath, "old hub\n")
+	writeFile(t, filepath.Join(dir, ".cursorrules"), "hand-written rules\n")
+
+	// .claude/ is read-only, so the claude link fails after cursor succeeded
+	claudeDir := filepath.Join(dir, ".claude")
+	require.NoError(t, os.MkdirAll(claudeDir, 0555))
+	defer func() { _ = os.Chmod(claudeDir, 0755) }()
+
+	commit := false
+	cfg := &config.Config{Targets: []string{"cursor", "claude"}, LocalOverlays: []string{"base.md"}, CommitOutputs: &commit}
+	result, err := Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{Atomic: true})
+	require.NoError(t, err)
+
+	assert.True(t, result.RolledBack)
+	assert.Empty(t, result.RollbackErrors)
+	assert.Equal(t, "rolled_back", result.HubStatus)
+	assert.Equal(t, "rolled_back", result.Links[0].Status)
+	assert.Equal(t, "error", result.Links[1].Status)
+	assert.Equal(t, []GitFileResult{{Path: ".gitignore", Status: "rolled_back"}}, result.GitFiles)
+	assert.NoFileExists(t, filepath.Join(dir, ".gitignore"))
+
+	hub, err := os.ReadFile(hubPath)
+	require.NoError(t, err)
+	assert.Equal(t, "old hub\n", string(hub))
+
+	info, err := os.Lstat(filepath.Join(dir, ".cursorrules"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular(), "cursor's file is restored in place of the symlink")
+	rules, err := os.ReadFile(filepath.Join(dir, ".cursorrules"))
+	require.NoError(t, err)
+	assert.Equal(t, "hand-written rules\n", string(rules))
+}
+
+func TestSync_AtomicRollsBackOnGitFileFailure(t *testing.T) {
+	skipOnWindows(t)
+	if os.Geteuid() == 0 {
+		t.Skip("read-only files are writable by root")
+	}
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "Content\n")
+	gitignore := filepath.Join(dir, ".gitignore")
+	writeFile(t, gitignore, "node_modules/\n")
+	require.NoError(t, os.Chmod(gitignore, 0444))
+
+	commit := false
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}, CommitOutputs: &commit}
+	result, err := Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{Atomic: true})
+	require.NoError(t, err)
+
+	assert.True(t, result.RolledBack)
+	assert.Empty(t, result.RollbackErrors)
+	assert.Equal(t, "rolled_back", result.HubStatus)
+	for _, l := range result.Links {
+		assert.Equal(t, "rolled_back", l.Status)
+	}
+	require.Len(t, result.GitFiles, 1)
+	assert.Equal(t, ".gitignore", result.GitFiles[0].Path)
+	assert.Equal(t, "error", result.GitFiles[0].Status)
+
+	assert.NoFileExists(t, filepath.Join(dir, ".ailign", "instructions.md"))
+	_, err = os.Lstat(filepath.Join(dir, ".cursorrules"))
+	assert.True(t, os.IsNotExist(err), "cursor's link is removed")
+	data, err := os.ReadFile(gitignore)
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules/\n", string(data))
+}
+
+func TestSync_AtomicStagingFailureTouchesNothing(t *testing.T) {
+	skipOnWindows(t)
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string)
+		wantErr string
+	}{
+		{"target", func(t *testing.T, dir string) {
+			// A directory where cursor's file belongs cannot be staged
+			writeFile(t, filepath.Join(dir, ".cursorrules", "keep.txt"), "x")
+		}, "cursor: cannot stage"},
+		{"git file", func(t *testing.T, dir string) {
+			writeFile(t, filepath.Join(dir, ".gitignore"), blockBegin+"\n/a\n")
+		}, ".gitignore: the ailign block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := resolveDir(t)
+			writeFile(t, filepath.Join(dir, "base.md"), "Content\n")
+			tt.setup(t, dir)
+
+			commit := false
+			cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}, CommitOutputs: &commit}
+			result, err := Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{Atomic: true})
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "staging changes (nothing was written)")
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			_, err = os.Stat(filepath.Join(dir, ".ailign", "instructions.md"))
+			assert.True(t, os.IsNotExist(err), "hub is never written")
+			_, err = os.Lstat(filepath.Join(dir, ".claude"))
+			assert.True(t, os.IsNotExist(err), "claude is never linked")
+		})
+	}
+}
+
+func TestSync_AtomicSuccess(t *testing.T) {
+	skipOnWindows(t)
+	dir := resolveDir(t)
+	writeFile(t, filepath.Join(dir, "base.md"), "Content\n")
+
+	cfg := &config.Config{Targets: []string{"claude", "cursor"}, LocalOverlays: []string{"base.md"}}
+	result, err := Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{Atomic: true})
+	require.NoError(t, err)
+	assert.False(t, result.RolledBack)
+	assert.Equal(t, "written", result.HubStatus)
+	for _, l := range result.Links {
+		assert.Equal(t, "created", l.Status)
+	}
+
+	again, err := Sync(dir, cfg, target.NewDefaultRegistry(), SyncOptions{Atomic: true})
+	require.NoError(t, err)
+	assert.Equal(t, "unchanged", again.HubStatus)
+	for _, l := range again.Links {
+		assert.Equal(t, "exists", l.Status)
+	}
+}
@@ -1,29 +1,132 @@
 package sync
 
+import "time"
+
 // ComposeResult holds the outcome of composing overlay files.
 type ComposeResult struct {
-	Content  []byte
-	Warnings []string
+	Content    []byte // hub file content: managed header followed by Body
+	Body       []byte // composed overlays without the header
+	Sources    []string
+	Warnings   []string
+	Operations []SectionOperation
+	Secrets    []SecretFinding // likely credentials in Body
+	Segments   []Segment       // Body split by the overlay each part came from
+	// Version and SourcesDigest are stamped into every managed header
+	// rendered from this result; see HeaderStamp.
+	Version       string
+	SourcesDigest string
+}
+
+// Segment is a run of composed text attributed to the overlay it came
+// from. Concatenated in order, a ComposeResult's segments equal its Body.
+type Segment struct {
+	Overlay string
+	Text    string
+}
+
+// SecretFinding is a likely credential detected in composed content.
+type SecretFinding struct {
+	Rule        string // e.g. "aws-access-key-id", "high-entropy"
+	Description string // human-readable kind, e.g. "AWS access key ID"
+	Overlay     string // overlay the text came from; empty if it can't be traced
+	Line        int    // 1-based line in Overlay, or in Body when Overlay is empty
+	Fingerprint string // stable identifier used by secrets.allowlist
+	Redacted    string // the match with most of it masked
+}
+
+// SectionOperation records a section directive applied during composition,
+// so the composed output can be traced back to the overlays that shaped it.
+type SectionOperation struct {
+	Overlay       string // overlay containing the directive
+	Line          int    // line of the directive's heading in Overlay
+	Action        string // "replace", "append" or "delete"
+	Heading       string // heading the directive targets, e.g. "## Testing"
+	TargetOverlay string // overlay the targeted section came from
+	TargetLine    int    // line of the targeted heading in TargetOverlay
 }
 
 // SyncResult holds the outcome of a sync operation.
 type SyncResult struct {
 	DryRun    bool
 	HubPath   string
-	HubStatus string // "written" or "unchanged"
+	HubStatus string // "written", "unchanged" or "rolled_back"
 	Links     []LinkResult
 	Warnings  []string
+	// RolledBack is set when an atomic sync failed and restored the hub
+	// and every link to their state before the run.
+	RolledBack bool
+	// RollbackErrors lists paths that could not be restored.
+	RollbackErrors []string
+	// UpToDate is set when the state manifest showed that nothing changed
+	// since the last sync, so composition and writes were skipped.
+	UpToDate bool
+	// GitFiles lists the .gitignore and .gitattributes files whose
+	// ailign block changed (or would, on a dry run) or failed to; see
+	// config.Config.CommitOutputs.
+	GitFiles []GitFileResult
+	// Sources lists the overlays this run composed, with their digests.
+	Sources []StateSource
 }
 
 // LinkResult holds the per-target symlink outcome.
 type LinkResult struct {
 	Target   string
 	LinkPath string
-	Status   string // "created", "exists", "replaced", "error"
+	Mode     string // "symlink" or "copy"
+	Status   string // "created", "exists", "replaced", "skipped", "removed", "rolled_back", "error"
 	Error    string
 }
 
+// ComposeOptions configures overlay composition.
+type ComposeOptions struct {
+	Dedupe    bool   // drop duplicate overlays, sections and paragraphs instead of only warning
+	OutputDir string // slash-separated directory, relative to baseDir, that relative links must resolve from; defaults to baseDir
+	Header    HeaderOptions
+	// HiddenUnicode and PromptInjection are severities ("error", "warning"
+	// or "off") for the content security checks. Empty means the default:
+	// hidden characters are errors, prompt injection checks are off.
+	HiddenUnicode   string
+	PromptInjection string
+	// Version is the ailign version recorded in the managed header.
+	Version string
+}
+
+// HeaderOptions customizes the managed-content header.
+type HeaderOptions struct {
+	Text   string // title line; defaults to "DO NOT EDIT — Generated by ailign"
+	Link   string // optional documentation URL
+	Format string // target.FormatMarkdown (default) or target.FormatPlain
+}
+
+// RenderOptions configures how composed content is rendered for a target.
+type RenderOptions struct {
+	HubPath    string // hub file path relative to the repository root
+	Header     HeaderOptions
+	OmitHeader bool // leave the managed header out entirely
+}
+
 // SyncOptions configures the sync operation.
 type SyncOptions struct {
 	DryRun bool
+	// Targets limits the run to these configured targets; empty means all.
+	Targets []string
+	// SkipTargets excludes these configured targets from the run.
+	SkipTargets []string
+	// Atomic applies all changes or none: if any target fails, the hub
+	// and every link are restored to their state before the run.
+	Atomic bool
+	// LockTimeout is how long to wait for a concurrent sync to release
+	// the repository's lock; zero fails at once if it is held. Dry runs
+	// don't take the lock.
+	LockTimeout time.Duration
+	// KeepOrphans leaves outputs of targets that are no longer configured
+	// in place instead of removing them.
+	KeepOrphans bool
+	// Force overwrites managed files that were edited since the last
+	// sync instead of failing with a *ModifiedError.
+	Force bool
+	// Version is the CLI version recorded in the state manifest and
+	// stamped into managed headers. A
+	// different version never skips work, since rendering may differ.
+	Version string
 }
@@ -5,3 +5,4 @@ type Claude struct{}
 
 func (Claude) Name() string            { return "claude" }
 func (Claude) InstructionPath() string { return ".claude/instructions.md" }
+func (Claude) Format() string          { return FormatMarkdown }
@@ -5,3 +5,4 @@ type Copilot struct{}
 
 func (Copilot) Name() string            { return "copilot" }
 func (Copilot) InstructionPath() string { return ".github/copilot-instructions.md" }
+func (Copilot) Format() string          { return FormatMarkdown }
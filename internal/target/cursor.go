@@ -5,3 +5,4 @@ type Cursor struct{}
 
 func (Cursor) Name() string            { return "cursor" }
 func (Cursor) InstructionPath() string { return ".cursorrules" }
+func (Cursor) Format() string          { return FormatPlain }
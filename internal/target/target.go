@@ -1,9 +1,29 @@
 package target
 
+// Instruction file formats. The format decides how generated content,
+// such as the managed header, is written for a target.
+const (
+	FormatMarkdown = "markdown"
+	FormatPlain    = "plain"
+)
+
 // Target defines the interface for an AI tool target.
 type Target interface {
 	Name() string
 	InstructionPath() string
+	Format() string
+}
+
+// CharLimit returns the maximum instruction size, in characters, that the
+// tool behind t reads, or 0 when no limit is known. Targets declare a
+// limit by implementing CharLimit() int. Of the built-in targets only
+// Windsurf documents one; Claude Code, Cursor and GitHub Copilot publish
+// no limit for their instruction files.
+func CharLimit(t Target) int {
+	if l, ok := t.(interface{ CharLimit() int }); ok {
+		return l.CharLimit()
+	}
+	return 0
 }
 
 // Registry holds all available target implementations.
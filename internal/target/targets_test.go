@@ -42,6 +42,13 @@ func TestWindsurf_InstructionPath(t *testing.T) {
 	assert.Equal(t, ".windsurfrules", Windsurf{}.InstructionPath())
 }
 
+func TestTargets_Format(t *testing.T) {
+	assert.Equal(t, FormatMarkdown, Claude{}.Format())
+	assert.Equal(t, FormatMarkdown, Copilot{}.Format())
+	assert.Equal(t, FormatPlain, Cursor{}.Format())
+	assert.Equal(t, FormatPlain, Windsurf{}.Format())
+}
+
 func TestAllTargets_ImplementInterface(t *testing.T) {
 	// Compile-time check that all types implement Target
 	var targets []Target
@@ -50,5 +57,6 @@ func TestAllTargets_ImplementInterface(t *testing.T) {
 	for _, tgt := range targets {
 		assert.NotEmpty(t, tgt.Name(), "Name() should not be empty")
 		assert.NotEmpty(t, tgt.InstructionPath(), "InstructionPath() should not be empty")
+		assert.Contains(t, []string{FormatMarkdown, FormatPlain}, tgt.Format())
 	}
 }
@@ -5,3 +5,8 @@ type Windsurf struct{}
 
 func (Windsurf) Name() string            { return "windsurf" }
 func (Windsurf) InstructionPath() string { return ".windsurfrules" }
+func (Windsurf) Format() string          { return FormatPlain }
+
+// CharLimit is Windsurf's documented per-file limit for rules files;
+// content beyond it is silently ignored.
+func (Windsurf) CharLimit() int { return 6000 }
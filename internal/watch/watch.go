@@ -0,0 +1,94 @@
+// Package watch reruns work when files change. It polls file metadata
+// rather than relying on OS notifications, so it behaves the same on
+// every platform and across editors that save by renaming.
+package watch
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Options configures Run.
+type Options struct {
+	Interval time.Duration // how often the files are checked
+	Debounce time.Duration // quiet period after the last change before fn runs
+}
+
+// stamp is the metadata compared between polls.
+type stamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// Run calls fn once, then again each time one of the files returned by
+// paths is created, modified or deleted, once no further change has been
+// seen for opts.Debounce. paths is called again after every run, so the
+// watched set can follow configuration changes. A change made while fn
+// runs triggers another run. Run returns when ctx is done.
+func Run(ctx context.Context, opts Options, paths func() []string, fn func()) {
+	files := paths()
+	var last map[string]stamp
+	run := func() {
+		// Stamps taken before fn, so that saves during it count as changes
+		before := take(files)
+		fn()
+		files = paths()
+		last = take(files)
+		for f, s := range before {
+			if _, ok := last[f]; ok {
+				last[f] = s
+			}
+		}
+	}
+	run()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	var changedAt time.Time // zero while no change is pending
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if current := take(files); !equal(current, last) {
+				last = current
+				changedAt = now
+				continue
+			}
+			if changedAt.IsZero() || now.Sub(changedAt) < opts.Debounce {
+				continue
+			}
+			changedAt = time.Time{}
+			run()
+		}
+	}
+}
+
+// take records the current metadata of each file.
+func take(files []string) map[string]stamp {
+	stamps := make(map[string]stamp, len(files))
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			stamps[f] = stamp{}
+			continue
+		}
+		stamps[f] = stamp{exists: true, size: info.Size(), modTime: info.ModTime()}
+	}
+	return stamps
+}
+
+func equal(a, b map[string]stamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for f, s := range a {
+		if t, ok := b[f]; !ok || !s.modTime.Equal(t.modTime) || s.size != t.size || s.exists != t.exists {
+			return false
+		}
+	}
+	return true
+}
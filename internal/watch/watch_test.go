@@ -0,0 +1,109 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{Interval: 10 * time.Millisecond, Debounce: 100 * time.Millisecond}
+
+// start runs Run in the background and returns the call counter.
+func start(t *testing.T, paths func() []string) *atomic.Int32 {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	var calls atomic.Int32
+	go func() {
+		defer close(done)
+		Run(ctx, testOptions, paths, func() { calls.Add(1) })
+	}()
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+	return &calls
+}
+
+func TestRun_RerunsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.md")
+	require.NoError(t, os.WriteFile(file, []byte("one"), 0644))
+
+	calls := start(t, func() []string { return []string{file} })
+
+	require.NoError(t, os.WriteFile(file, []byte("two!"), 0644))
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.Remove(file))
+	assert.Eventually(t, func() bool { return calls.Load() == 3 }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestRun_RerunsOnChangeDuringRun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.md")
+	require.NoError(t, os.WriteFile(file, []byte("one"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	var calls atomic.Int32
+	go func() {
+		defer close(done)
+		Run(ctx, testOptions, func() []string { return []string{file} }, func() {
+			// The overlay is saved while the first sync is still running
+			if calls.Add(1) == 1 {
+				require.NoError(t, os.WriteFile(file, []byte("two!"), 0644))
+			}
+		})
+	}()
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestRun_DebouncesBursts(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.md")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0644))
+
+	calls := start(t, func() []string { return []string{file} })
+
+	content := "x"
+	for i := 0; i < 5; i++ {
+		content += "x"
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		time.Sleep(20 * time.Millisecond)
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 5*time.Millisecond)
+	time.Sleep(3 * testOptions.Debounce)
+	assert.Equal(t, int32(2), calls.Load(), "a burst of writes runs once")
+}
+
+func TestRun_FollowsNewPaths(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.md")
+	second := filepath.Join(dir, "b.md")
+	require.NoError(t, os.WriteFile(first, []byte("a"), 0644))
+
+	var watched atomic.Value
+	watched.Store([]string{first})
+	calls := start(t, func() []string { return watched.Load().([]string) })
+
+	watched.Store([]string{first, second})
+	require.NoError(t, os.WriteFile(first, []byte("aa"), 0644))
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(second, []byte("b"), 0644))
+	assert.Eventually(t, func() bool { return calls.Load() == 3 }, 2*time.Second, 5*time.Millisecond)
+}